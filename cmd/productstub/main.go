@@ -0,0 +1,83 @@
+// Command productstub serve um catálogo de produtos local com o contrato da
+// FakeStore, para rodar o serviço sem depender de fakestoreapi.com:
+//
+//	go run ./cmd/productstub -addr :8081
+//	PRODUCT_SERVICE_URL=http://localhost:8081 go run ./cmd/server
+//
+// As falhas podem ser alteradas com o servidor no ar via PUT /_stub/faults.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/vinihss/aiqfome/internal/infrastructure/productstub"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "endereço em que o stub escuta")
+	fixtures := flag.String("fixtures", "", "arquivo JSON com os produtos (padrão: fixtures embutidas)")
+	latency := flag.Duration("latency", 0, "latência somada a cada resposta")
+	failNext := flag.Int("fail-next", 0, "número de requisições iniciais respondidas com -fail-status")
+	failStatus := flag.Int("fail-status", http.StatusServiceUnavailable, "status das falhas injetadas")
+	missing := flag.String("missing", "", "IDs respondidos com 404, separados por vírgula")
+	flag.Parse()
+
+	products, err := productstub.LoadFixtures(*fixtures)
+	if err != nil {
+		log.Fatalf("Failed to load fixtures: %v", err)
+	}
+	missingIDs, err := parseIDs(*missing)
+	if err != nil {
+		log.Fatalf("Invalid -missing: %v", err)
+	}
+
+	stub := productstub.New(products)
+	stub.SetFaults(productstub.Faults{
+		Latency:    *latency,
+		FailNext:   *failNext,
+		FailStatus: *failStatus,
+		Missing:    missingIDs,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{Addr: *addr, Handler: stub, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("product stub serving %d products on %s", len(products), *addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Failed to run product stub: %v", err)
+	}
+}
+
+func parseIDs(s string) ([]uint, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var ids []uint
+	for _, part := range strings.Split(s, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid product id %q", part)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
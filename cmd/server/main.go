@@ -1,64 +1,58 @@
 package main
 
 import (
-	"errors"
-	"fmt"
-	"github.com/gin-gonic/gin"
+	"context"
 	"github.com/vinihss/aiqfome/config"
 	_ "github.com/vinihss/aiqfome/docs"
-	"github.com/vinihss/aiqfome/internal/infrastructure/database/models"
-	"github.com/vinihss/aiqfome/internal/routes"
+	"github.com/vinihss/aiqfome/internal/app"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
-type Server struct {
-	addr string
-}
-
-func NewServer(addr string) *Server {
-	return &Server{addr: addr}
-}
-
-func (s *Server) Run() error {
-	config.ConnectDB()
-	err := config.DB.AutoMigrate(&models.Favorite{}, &models.Customer{})
-	if err != nil {
-		return errors.New(fmt.Sprintf("Error migrating database: %v", err))
-	}
-
-	// Set up Gin router
-	r := gin.Default()
-	routes.SetupRoutes(r)
-
-	err = r.Run(s.addr)
-	if err != nil {
-		return errors.New(fmt.Sprintf("Error starting server: %v", err))
-	}
-	// Start the server
-	return nil
-}
-
 // @title Favorite API
 // @version 1.0
 // @description API para favoritar produtos
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 // @BasePath /
 func main() {
 
-	// load env from os
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, cfg, os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "sync-favorites" {
+		if err := runSyncFavorites(ctx, cfg, os.Stdout); err != nil {
+			log.Fatalf("Favorites sync failed: %v", err)
+		}
+		return
+	}
+
+	a, err := app.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to build application: %v", err)
 	}
 
-	server := NewServer(":8080")
-	err := server.Run()
+	server := NewServer(a)
+	err = server.Run(ctx)
 	if err != nil {
 		log.Fatalf("Failed to run server: %v", err)
-		os.Exit(1)
 	}
 }
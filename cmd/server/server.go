@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/vinihss/aiqfome/internal/app"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/migrations"
+)
+
+type Server struct {
+	app     *app.App
+	handler http.Handler
+}
+
+func NewServer(a *app.App) *Server {
+	return &Server{app: a, handler: a.Router()}
+}
+
+// Run aplica as migrações pendentes, inicia os hooks e sobe o servidor HTTP,
+// bloqueando até ctx ser cancelado ou o servidor falhar. No encerramento, as
+// conexões em andamento são drenadas dentro do prazo configurado e, em
+// seguida, os hooks são encerrados em ordem inversa.
+func (s *Server) Run(ctx context.Context) error {
+	cfg := s.app.Config
+
+	// As migrações rodam antes dos hooks para que workers como a
+	// sincronização dos favoritos já encontrem o schema atualizado.
+	if s.app.DB != nil && cfg.Database.AutoMigrate {
+		if err := s.migrate(ctx); err != nil {
+			return fmt.Errorf("Error migrating database: %w", err)
+		}
+	}
+
+	if err := s.app.Lifecycle.Start(ctx); err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:         cfg.Server.Addr(),
+		Handler:      s.handler,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("listening on %s", srv.Addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	var runErr error
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			runErr = fmt.Errorf("Error starting server: %w", err)
+		}
+	case <-ctx.Done():
+		log.Printf("shutting down, draining connections for up to %s", cfg.Server.ShutdownTimeout)
+	}
+
+	return errors.Join(runErr, s.shutdown(srv))
+}
+
+func (s *Server) migrate(ctx context.Context) error {
+	sqlDB, err := s.app.DB.DB()
+	if err != nil {
+		return err
+	}
+	migrator, err := migrations.NewMigrator(sqlDB)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(ctx)
+	for _, m := range applied {
+		log.Printf("applied migration %04d_%s", m.Version, m.Name)
+	}
+	return err
+}
+
+func (s *Server) shutdown(srv *http.Server) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.app.Config.Server.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := srv.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("draining http server: %w", err))
+	}
+	if err := s.app.Lifecycle.Stop(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vinihss/aiqfome/config"
+	"github.com/vinihss/aiqfome/internal/app"
+	"github.com/vinihss/aiqfome/internal/lifecycle"
+)
+
+// freePort reserva uma porta livre e a libera para o servidor usar.
+func freePort(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	return port
+}
+
+// events registra, em ordem, o que acontece durante o encerramento.
+type events struct {
+	mutex sync.Mutex
+	list  []string
+}
+
+func (e *events) add(event string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.list = append(e.list, event)
+}
+
+func (e *events) String() string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return strings.Join(e.list, ", ")
+}
+
+func newTestServer(t *testing.T, handler http.Handler, hook lifecycle.Hook, shutdownTimeout time.Duration) (*Server, string) {
+	t.Helper()
+	port := freePort(t)
+	registry := lifecycle.NewRegistry()
+	registry.Append(hook)
+	a := &app.App{
+		Config: &config.AppConfig{Server: config.ServerConfig{
+			Port:            port,
+			ReadTimeout:     time.Second,
+			WriteTimeout:    time.Second,
+			ShutdownTimeout: shutdownTimeout,
+		}},
+		Lifecycle: registry,
+	}
+	return &Server{app: a, handler: handler}, "http://127.0.0.1:" + port
+}
+
+// waitListening espera o servidor aceitar conexões.
+func waitListening(t *testing.T, baseURL string) {
+	t.Helper()
+	addr := strings.TrimPrefix(baseURL, "http://")
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("server did not start listening on %s", addr)
+}
+
+func TestRunDrainsInFlightRequestsBeforeReturning(t *testing.T) {
+	log := &events{}
+	inFlight := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(inFlight)
+		<-release
+		log.add("request done")
+		io.WriteString(w, "ok")
+	})
+	hook := lifecycle.Hook{
+		Name:    "worker",
+		OnStart: func(context.Context) error { log.add("hook started"); return nil },
+		OnStop:  func(context.Context) error { log.add("hook stopped"); return nil },
+	}
+	server, baseURL := newTestServer(t, handler, hook, 5*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() { runErr <- server.Run(ctx) }()
+	waitListening(t, baseURL)
+
+	type result struct {
+		body string
+		err  error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(baseURL + "/slow")
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		response <- result{body: string(body), err: err}
+	}()
+	<-inFlight
+
+	cancel()
+	select {
+	case err := <-runErr:
+		t.Fatalf("Run returned %v while a request was in flight", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Errorf("Run = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after the request was drained")
+	}
+
+	if got := <-response; got.err != nil || got.body != "ok" {
+		t.Errorf("in-flight request = %q, %v; want it completed", got.body, got.err)
+	}
+	if got, want := log.String(), "hook started, request done, hook stopped"; got != want {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+}
+
+func TestRunGivesUpDrainingAfterShutdownTimeout(t *testing.T) {
+	inFlight := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(inFlight)
+		<-release
+	})
+	stopped := make(chan struct{})
+	hook := lifecycle.Hook{
+		Name:   "worker",
+		OnStop: func(context.Context) error { close(stopped); return nil },
+	}
+	server, baseURL := newTestServer(t, handler, hook, 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- server.Run(ctx) }()
+	waitListening(t, baseURL)
+
+	go func() {
+		if resp, err := http.Get(baseURL + "/stuck"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-inFlight
+	cancel()
+
+	select {
+	case err := <-runErr:
+		if err == nil || !strings.Contains(err.Error(), "draining http server") {
+			t.Errorf("Run = %v, want a draining error", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after the shutdown timeout")
+	}
+	// Os hooks são encerrados mesmo quando a drenagem estoura o prazo.
+	select {
+	case <-stopped:
+	default:
+		t.Error("lifecycle hooks were not stopped")
+	}
+}
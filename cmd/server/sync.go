@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/vinihss/aiqfome/config"
+	"github.com/vinihss/aiqfome/internal/app"
+)
+
+// runSyncFavorites executa o subcomando "sync-favorites": uma passada da
+// sincronização dos favoritos com o catálogo, sem subir o servidor HTTP.
+func runSyncFavorites(ctx context.Context, cfg *config.AppConfig, out io.Writer) (err error) {
+	// A passada roda aqui; o agendamento em segundo plano não é necessário.
+	cfg.FavoritesSync.Enabled = false
+
+	a, err := app.New(cfg)
+	if err != nil {
+		return err
+	}
+	if a.FavoriteSync == nil {
+		return errors.New("favorite repository does not support snapshot sync")
+	}
+	if err := a.Lifecycle.Start(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, a.Lifecycle.Stop(context.Background()))
+	}()
+
+	report, err := a.FavoriteSync.Execute(ctx)
+	fmt.Fprintf(out, "products: %d, updated: %d, unavailable: %d, failed: %d\n",
+		report.Products, report.Updated, report.Unavailable, report.Failed)
+	return err
+}
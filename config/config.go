@@ -0,0 +1,606 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AppConfig agrupa toda a configuração do serviço. É carregada uma única vez
+// em Load e injetada nos componentes que precisam dela.
+type AppConfig struct {
+	Env      string         `yaml:"env"`
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Redis    RedisConfig    `yaml:"redis"`
+	JWT      JWTConfig      `yaml:"jwt"`
+	Product  ProductConfig  `yaml:"product"`
+
+	RateLimit     RateLimitConfig     `yaml:"rate_limit"`
+	FavoritesSync FavoritesSyncConfig `yaml:"favorites_sync"`
+	Alerts        AlertsConfig        `yaml:"alerts"`
+}
+
+type ServerConfig struct {
+	Port            string        `yaml:"port"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// TrustedProxies lista os IPs ou CIDRs dos proxies cujo X-Forwarded-For
+	// é aceito para descobrir o IP do cliente. Vazio, o padrão, usa sempre o
+	// endereço da conexão, para que o header não burle o rate limit por IP.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+func (s ServerConfig) Addr() string {
+	return ":" + s.Port
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+}
+
+type JWTConfig struct {
+	Issuer     string        `yaml:"issuer"`
+	Audience   string        `yaml:"audience"`
+	TTL        time.Duration `yaml:"ttl"` // validade do access token
+	RefreshTTL time.Duration `yaml:"refresh_ttl"`
+
+	// SigningKeyFile aponta para a chave privada (RSA ou Ed25519, PEM) usada
+	// para assinar. Sem ela, uma chave efêmera é gerada, o que só é aceito
+	// fora de produção.
+	SigningKeyFile string `yaml:"signing_key_file"`
+	SigningKeyID   string `yaml:"signing_key_id"`
+	// VerificationKeys lista chaves públicas antigas ainda aceitas, no
+	// formato "kid=caminho.pem,kid2=outro.pem".
+	VerificationKeys string `yaml:"verification_keys"`
+}
+
+type ProductConfig struct {
+	// Provider escolhe a fonte do catálogo: "fakestore", "aiqfome" (serviço
+	// interno em BaseURL, autenticado com ServiceToken) ou "static" (arquivo
+	// JSON em CatalogFile).
+	Provider     string        `yaml:"provider"`
+	BaseURL      string        `yaml:"base_url"`
+	ServiceToken string        `yaml:"service_token"`
+	CatalogFile  string        `yaml:"catalog_file"`
+	Timeout      time.Duration `yaml:"timeout"`
+
+	CacheTTL         time.Duration `yaml:"cache_ttl"`
+	CacheNotFoundTTL time.Duration `yaml:"cache_not_found_ttl"`
+	// CacheStaleTTL é por quanto tempo após expirar um produto ainda pode
+	// ser servido enquanto o circuit breaker estiver aberto.
+	CacheStaleTTL   time.Duration `yaml:"cache_stale_ttl"`
+	CacheMaxEntries int           `yaml:"cache_max_entries"`
+
+	// O circuit breaker abre com BreakerFailures falhas dentro de
+	// BreakerWindow e volta a testar a API depois de BreakerCooldown.
+	BreakerFailures       int           `yaml:"breaker_failures"`
+	BreakerWindow         time.Duration `yaml:"breaker_window"`
+	BreakerCooldown       time.Duration `yaml:"breaker_cooldown"`
+	BreakerHalfOpenProbes int           `yaml:"breaker_half_open_probes"`
+
+	// Timeout vale para cada tentativa; entre elas a espera cresce
+	// exponencialmente a partir de RetryBaseDelay, até RetryMaxDelay.
+	RetryMaxAttempts int           `yaml:"retry_max_attempts"`
+	RetryBaseDelay   time.Duration `yaml:"retry_base_delay"`
+	RetryMaxDelay    time.Duration `yaml:"retry_max_delay"`
+	// RetryStatuses são os status HTTP que justificam uma nova tentativa.
+	RetryStatuses []int `yaml:"retry_statuses"`
+}
+
+// RateLimit é o número máximo de requisições aceitas em uma janela
+// deslizante. Nas variáveis de ambiente é escrito como "60/1m".
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Window   time.Duration `yaml:"window"`
+}
+
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+// RateLimitConfig define os limites de cada grupo de rotas. Rotas públicas
+// são limitadas por IP; as autenticadas, pelo cliente ou API key.
+type RateLimitConfig struct {
+	Enabled        bool      `yaml:"enabled"`
+	Public         RateLimit `yaml:"public"`
+	API            RateLimit `yaml:"api"`
+	FavoritesWrite RateLimit `yaml:"favorites_write"`
+}
+
+// FavoritesSyncConfig controla a atualização periódica dos dados de produto
+// copiados para os favoritos.
+type FavoritesSyncConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"`
+	// MaxAge é a idade a partir da qual um favorito volta a ser sincronizado.
+	MaxAge        time.Duration `yaml:"max_age"`
+	BatchSize     int           `yaml:"batch_size"`
+	RatePerSecond int           `yaml:"rate_per_second"`
+}
+
+// AlertsConfig controla a entrega dos alertas de preço. Por enquanto o único
+// canal é o log: sem LogFile os alertas vão para o log do processo.
+type AlertsConfig struct {
+	LogFile string `yaml:"log_file"`
+}
+
+func defaultConfig() AppConfig {
+	return AppConfig{
+		Env: "development",
+		Server: ServerConfig{
+			Port:            "8080",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Host:            "postgres",
+			Port:            "5432",
+			User:            "postgres",
+			Password:        "postgres",
+			DbName:          "favorites",
+			SSLMode:         "disable",
+			MaxConns:        20,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			AutoMigrate:     true,
+		},
+		Redis: RedisConfig{
+			Addr: "redis:6379",
+		},
+		JWT: JWTConfig{
+			Issuer:     "aiqfome",
+			Audience:   "aiqfome-api",
+			TTL:        15 * time.Minute,
+			RefreshTTL: 30 * 24 * time.Hour,
+		},
+		Product: ProductConfig{
+			Provider:         "fakestore",
+			BaseURL:          "https://fakestoreapi.com",
+			Timeout:          3 * time.Second,
+			CacheTTL:         15 * time.Minute,
+			CacheNotFoundTTL: time.Minute,
+			CacheStaleTTL:    time.Hour,
+			CacheMaxEntries:  10000,
+
+			BreakerFailures:       5,
+			BreakerWindow:         30 * time.Second,
+			BreakerCooldown:       time.Minute,
+			BreakerHalfOpenProbes: 2,
+
+			RetryMaxAttempts: 3,
+			RetryBaseDelay:   100 * time.Millisecond,
+			RetryMaxDelay:    2 * time.Second,
+			RetryStatuses:    []int{429, 500, 502, 503, 504},
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:        true,
+			Public:         RateLimit{Requests: 20, Window: time.Minute},
+			API:            RateLimit{Requests: 300, Window: time.Minute},
+			FavoritesWrite: RateLimit{Requests: 30, Window: time.Minute},
+		},
+		FavoritesSync: FavoritesSyncConfig{
+			Enabled:       true,
+			Interval:      time.Hour,
+			MaxAge:        6 * time.Hour,
+			BatchSize:     50,
+			RatePerSecond: 5,
+		},
+	}
+}
+
+// Load monta a configuração na seguinte ordem de precedência (a última vence):
+// valores padrão, arquivo YAML apontado por CONFIG_FILE e variáveis de ambiente.
+// Um arquivo .env (ou o indicado por ENV_FILE) é lido sem sobrescrever
+// variáveis já definidas no processo.
+func Load() (*AppConfig, error) {
+	envFile := os.Getenv("ENV_FILE")
+	if err := loadDotEnv(envFile); err != nil {
+		return nil, err
+	}
+
+	cfg := defaultConfig()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadYAML(path, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// Validate verifica se a configuração é utilizável e devolve todos os
+// problemas encontrados de uma só vez.
+func (cfg *AppConfig) Validate() error {
+	var errs []error
+
+	if port, err := strconv.Atoi(cfg.Server.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("PORT must be a number between 1 and 65535, got %q", cfg.Server.Port))
+	}
+	if cfg.Server.ReadTimeout <= 0 || cfg.Server.WriteTimeout <= 0 || cfg.Server.IdleTimeout <= 0 {
+		errs = append(errs, errors.New("server timeouts must be greater than zero"))
+	}
+	for _, proxy := range cfg.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Errorf("TRUSTED_PROXIES must list IPs or CIDRs, got %q", proxy))
+		}
+	}
+	if cfg.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be greater than zero"))
+	}
+
+	if cfg.Database.Host == "" {
+		errs = append(errs, errors.New("database host is required (DATABASE_URL or DB_HOST)"))
+	}
+	if cfg.Database.User == "" {
+		errs = append(errs, errors.New("database user is required (DATABASE_URL or DB_USER)"))
+	}
+	if cfg.Database.DbName == "" {
+		errs = append(errs, errors.New("database name is required (DATABASE_URL or DB_NAME)"))
+	}
+	if cfg.Database.MaxConns < 1 {
+		errs = append(errs, fmt.Errorf("PG_MAX_CONNS must be at least 1, got %d", cfg.Database.MaxConns))
+	}
+	if cfg.Database.MaxIdleConns < 0 || cfg.Database.MaxIdleConns > cfg.Database.MaxConns {
+		errs = append(errs, fmt.Errorf("PG_MAX_IDLE_CONNS must be between 0 and PG_MAX_CONNS, got %d", cfg.Database.MaxIdleConns))
+	}
+
+	if cfg.Redis.Addr == "" {
+		errs = append(errs, errors.New("REDIS_ADDR is required"))
+	}
+	if cfg.Redis.DB < 0 {
+		errs = append(errs, fmt.Errorf("REDIS_DB must not be negative, got %d", cfg.Redis.DB))
+	}
+
+	if cfg.JWT.Issuer == "" || cfg.JWT.Audience == "" {
+		errs = append(errs, errors.New("JWT_ISSUER and JWT_AUDIENCE are required"))
+	}
+	if cfg.JWT.SigningKeyFile == "" && cfg.Env == "production" {
+		errs = append(errs, errors.New("JWT_SIGNING_KEY_FILE is required in production"))
+	}
+	if cfg.JWT.SigningKeyFile != "" && cfg.JWT.SigningKeyID == "" {
+		errs = append(errs, errors.New("JWT_SIGNING_KEY_ID is required when JWT_SIGNING_KEY_FILE is set"))
+	}
+	if cfg.JWT.TTL <= 0 {
+		errs = append(errs, errors.New("JWT_TTL must be greater than zero"))
+	}
+	if cfg.JWT.RefreshTTL <= cfg.JWT.TTL {
+		errs = append(errs, errors.New("JWT_REFRESH_TTL must be greater than JWT_TTL"))
+	}
+
+	switch cfg.Product.Provider {
+	case "":
+		errs = append(errs, errors.New("PRODUCT_PROVIDER is required"))
+	case "static":
+		if cfg.Product.CatalogFile == "" {
+			errs = append(errs, errors.New("PRODUCT_CATALOG_FILE is required when PRODUCT_PROVIDER is static"))
+		}
+	default:
+		if u, err := url.Parse(cfg.Product.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("PRODUCT_SERVICE_URL must be an absolute URL, got %q", cfg.Product.BaseURL))
+		}
+	}
+	if cfg.Product.Timeout <= 0 {
+		errs = append(errs, errors.New("PRODUCT_SERVICE_TIMEOUT must be greater than zero"))
+	}
+	if cfg.Product.CacheTTL <= 0 || cfg.Product.CacheNotFoundTTL <= 0 {
+		errs = append(errs, errors.New("PRODUCT_CACHE_TTL and PRODUCT_CACHE_NOT_FOUND_TTL must be greater than zero"))
+	}
+	if cfg.Product.CacheStaleTTL < 0 {
+		errs = append(errs, errors.New("PRODUCT_CACHE_STALE_TTL must not be negative"))
+	}
+	if cfg.Product.CacheMaxEntries < 1 {
+		errs = append(errs, fmt.Errorf("PRODUCT_CACHE_MAX_ENTRIES must be at least 1, got %d", cfg.Product.CacheMaxEntries))
+	}
+	if cfg.Product.BreakerFailures < 1 || cfg.Product.BreakerHalfOpenProbes < 1 {
+		errs = append(errs, errors.New("PRODUCT_BREAKER_FAILURES and PRODUCT_BREAKER_HALF_OPEN_PROBES must be at least 1"))
+	}
+	if cfg.Product.BreakerWindow <= 0 || cfg.Product.BreakerCooldown <= 0 {
+		errs = append(errs, errors.New("PRODUCT_BREAKER_WINDOW and PRODUCT_BREAKER_COOLDOWN must be greater than zero"))
+	}
+	if cfg.Product.RetryMaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("PRODUCT_RETRY_MAX_ATTEMPTS must be at least 1, got %d", cfg.Product.RetryMaxAttempts))
+	}
+	if cfg.Product.RetryBaseDelay <= 0 || cfg.Product.RetryMaxDelay < cfg.Product.RetryBaseDelay {
+		errs = append(errs, errors.New("PRODUCT_RETRY_BASE_DELAY must be greater than zero and not above PRODUCT_RETRY_MAX_DELAY"))
+	}
+	for _, status := range cfg.Product.RetryStatuses {
+		if status != 408 && status != 429 && (status < 500 || status > 599) {
+			errs = append(errs, fmt.Errorf("PRODUCT_RETRY_STATUSES must only list 408, 429 or 5xx statuses, got %d", status))
+		}
+	}
+
+	if cfg.RateLimit.Enabled {
+		limits := []struct {
+			name  string
+			limit RateLimit
+		}{
+			{"RATE_LIMIT_PUBLIC", cfg.RateLimit.Public},
+			{"RATE_LIMIT_API", cfg.RateLimit.API},
+			{"RATE_LIMIT_FAVORITES_WRITE", cfg.RateLimit.FavoritesWrite},
+		}
+		for _, l := range limits {
+			if l.limit.Requests < 1 || l.limit.Window < time.Second {
+				errs = append(errs, fmt.Errorf("%s must allow at least 1 request per window of 1s or more, got %s", l.name, l.limit))
+			}
+		}
+	}
+
+	if cfg.FavoritesSync.Interval <= 0 || cfg.FavoritesSync.MaxAge <= 0 {
+		errs = append(errs, errors.New("FAVORITES_SYNC_INTERVAL and FAVORITES_SYNC_MAX_AGE must be greater than zero"))
+	}
+	if cfg.FavoritesSync.BatchSize < 1 || cfg.FavoritesSync.RatePerSecond < 1 {
+		errs = append(errs, errors.New("FAVORITES_SYNC_BATCH_SIZE and FAVORITES_SYNC_RATE_PER_SECOND must be at least 1"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func applyEnv(cfg *AppConfig) error {
+	var errs []error
+
+	setString("APP_ENV", &cfg.Env)
+
+	setString("PORT", &cfg.Server.Port)
+	errs = append(errs,
+		setDuration("SERVER_READ_TIMEOUT", &cfg.Server.ReadTimeout),
+		setDuration("SERVER_WRITE_TIMEOUT", &cfg.Server.WriteTimeout),
+		setDuration("SERVER_IDLE_TIMEOUT", &cfg.Server.IdleTimeout),
+		setDuration("SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout),
+	)
+	setStringList("TRUSTED_PROXIES", &cfg.Server.TrustedProxies)
+
+	if dsn := os.Getenv("DATABASE_URL"); dsn != "" {
+		errs = append(errs, cfg.Database.parseURL(dsn))
+	}
+	setString("DB_HOST", &cfg.Database.Host)
+	setString("DB_PORT", &cfg.Database.Port)
+	setString("DB_USER", &cfg.Database.User)
+	setString("DB_PASSWORD", &cfg.Database.Password)
+	setString("DB_NAME", &cfg.Database.DbName)
+	setString("DB_SSLMODE", &cfg.Database.SSLMode)
+	errs = append(errs,
+		setInt("PG_MAX_CONNS", &cfg.Database.MaxConns),
+		setInt("PG_MAX_IDLE_CONNS", &cfg.Database.MaxIdleConns),
+		setDuration("PG_CONN_MAX_LIFETIME", &cfg.Database.ConnMaxLifetime),
+		setBool("DB_AUTO_MIGRATE", &cfg.Database.AutoMigrate),
+	)
+
+	setString("REDIS_ADDR", &cfg.Redis.Addr)
+	setString("REDIS_PASSWORD", &cfg.Redis.Password)
+	errs = append(errs, setInt("REDIS_DB", &cfg.Redis.DB))
+
+	setString("JWT_ISSUER", &cfg.JWT.Issuer)
+	setString("JWT_AUDIENCE", &cfg.JWT.Audience)
+	setString("JWT_SIGNING_KEY_FILE", &cfg.JWT.SigningKeyFile)
+	setString("JWT_SIGNING_KEY_ID", &cfg.JWT.SigningKeyID)
+	setString("JWT_VERIFICATION_KEYS", &cfg.JWT.VerificationKeys)
+	errs = append(errs,
+		setDuration("JWT_TTL", &cfg.JWT.TTL),
+		setDuration("JWT_REFRESH_TTL", &cfg.JWT.RefreshTTL),
+	)
+
+	setString("PRODUCT_PROVIDER", &cfg.Product.Provider)
+	setString("PRODUCT_SERVICE_URL", &cfg.Product.BaseURL)
+	setString("PRODUCT_SERVICE_TOKEN", &cfg.Product.ServiceToken)
+	setString("PRODUCT_CATALOG_FILE", &cfg.Product.CatalogFile)
+	errs = append(errs,
+		setDuration("PRODUCT_SERVICE_TIMEOUT", &cfg.Product.Timeout),
+		setDuration("PRODUCT_CACHE_TTL", &cfg.Product.CacheTTL),
+		setDuration("PRODUCT_CACHE_NOT_FOUND_TTL", &cfg.Product.CacheNotFoundTTL),
+		setDuration("PRODUCT_CACHE_STALE_TTL", &cfg.Product.CacheStaleTTL),
+		setInt("PRODUCT_CACHE_MAX_ENTRIES", &cfg.Product.CacheMaxEntries),
+		setInt("PRODUCT_BREAKER_FAILURES", &cfg.Product.BreakerFailures),
+		setDuration("PRODUCT_BREAKER_WINDOW", &cfg.Product.BreakerWindow),
+		setDuration("PRODUCT_BREAKER_COOLDOWN", &cfg.Product.BreakerCooldown),
+		setInt("PRODUCT_BREAKER_HALF_OPEN_PROBES", &cfg.Product.BreakerHalfOpenProbes),
+		setInt("PRODUCT_RETRY_MAX_ATTEMPTS", &cfg.Product.RetryMaxAttempts),
+		setDuration("PRODUCT_RETRY_BASE_DELAY", &cfg.Product.RetryBaseDelay),
+		setDuration("PRODUCT_RETRY_MAX_DELAY", &cfg.Product.RetryMaxDelay),
+		setIntList("PRODUCT_RETRY_STATUSES", &cfg.Product.RetryStatuses),
+	)
+
+	errs = append(errs,
+		setBool("RATE_LIMIT_ENABLED", &cfg.RateLimit.Enabled),
+		setRateLimit("RATE_LIMIT_PUBLIC", &cfg.RateLimit.Public),
+		setRateLimit("RATE_LIMIT_API", &cfg.RateLimit.API),
+		setRateLimit("RATE_LIMIT_FAVORITES_WRITE", &cfg.RateLimit.FavoritesWrite),
+	)
+
+	errs = append(errs,
+		setBool("FAVORITES_SYNC_ENABLED", &cfg.FavoritesSync.Enabled),
+		setDuration("FAVORITES_SYNC_INTERVAL", &cfg.FavoritesSync.Interval),
+		setDuration("FAVORITES_SYNC_MAX_AGE", &cfg.FavoritesSync.MaxAge),
+		setInt("FAVORITES_SYNC_BATCH_SIZE", &cfg.FavoritesSync.BatchSize),
+		setInt("FAVORITES_SYNC_RATE_PER_SECOND", &cfg.FavoritesSync.RatePerSecond),
+	)
+
+	setString("ALERTS_LOG_FILE", &cfg.Alerts.LogFile)
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid environment: %w", err)
+	}
+	return nil
+}
+
+func setString(key string, dst *string) {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		*dst = v
+	}
+}
+
+func setInt(key string, dst *int) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%s must be an integer, got %q", key, v)
+	}
+	*dst = n
+	return nil
+}
+
+// setStringList lê uma lista separada por vírgulas, como
+// "10.0.0.0/8,192.168.0.1". O valor "none" esvazia a lista.
+func setStringList(key string, dst *[]string) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
+	}
+	out := []string{}
+	if v != "none" {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	*dst = out
+}
+
+// setIntList lê uma lista separada por vírgulas, como "429,502,503". O valor
+// "none" esvazia a lista.
+func setIntList(key string, dst *[]int) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	out := []int{}
+	if v != "none" {
+		for _, item := range strings.Split(v, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(item))
+			if err != nil {
+				return fmt.Errorf("%s must be a comma-separated list of integers, got %q", key, v)
+			}
+			out = append(out, n)
+		}
+	}
+	*dst = out
+	return nil
+}
+
+func setBool(key string, dst *bool) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("%s must be true or false, got %q", key, v)
+	}
+	*dst = b
+	return nil
+}
+
+func setDuration(key string, dst *time.Duration) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s must be a duration such as 5s or 1m, got %q", key, v)
+	}
+	*dst = d
+	return nil
+}
+
+func setRateLimit(key string, dst *RateLimit) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	requests, window, found := strings.Cut(v, "/")
+	n, err := strconv.Atoi(requests)
+	if !found || err != nil {
+		return fmt.Errorf("%s must look like 60/1m, got %q", key, v)
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil {
+		return fmt.Errorf("%s must look like 60/1m, got %q", key, v)
+	}
+	*dst = RateLimit{Requests: n, Window: d}
+	return nil
+}
+
+func loadYAML(path string, cfg *AppConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadDotEnv lê pares CHAVE=valor de um arquivo .env. O arquivo é opcional
+// quando nenhum caminho explícito foi informado.
+func loadDotEnv(path string) error {
+	explicit := path != ""
+	if !explicit {
+		path = ".env"
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading env file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		key, value, found := strings.Cut(text, "=")
+		if !found {
+			return fmt.Errorf("%s:%d: expected KEY=value", path, line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		if _, set := os.LookupEnv(key); !set {
+			if err := os.Setenv(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,462 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"github.com/vinihss/aiqfome/config"
+	alertdomain "github.com/vinihss/aiqfome/internal/domain/alert"
+	authdomain "github.com/vinihss/aiqfome/internal/domain/authentication"
+	favoritedomain "github.com/vinihss/aiqfome/internal/domain/favorite"
+	productdomain "github.com/vinihss/aiqfome/internal/domain/product"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/repositories"
+	"github.com/vinihss/aiqfome/internal/infrastructure/external_epis"
+	"github.com/vinihss/aiqfome/internal/infrastructure/health"
+	"github.com/vinihss/aiqfome/internal/infrastructure/httpretry"
+	"github.com/vinihss/aiqfome/internal/infrastructure/notifications"
+	"github.com/vinihss/aiqfome/internal/infrastructure/ratelimit"
+	"github.com/vinihss/aiqfome/internal/infrastructure/sessions"
+	"github.com/vinihss/aiqfome/internal/infrastructure/signing"
+	http_interfaces_alert "github.com/vinihss/aiqfome/internal/interfaces/http/alert"
+	http_interfaces_apikey "github.com/vinihss/aiqfome/internal/interfaces/http/apikey"
+	http_interfaces_authentication "github.com/vinihss/aiqfome/internal/interfaces/http/authentcation"
+	http_interfaces_customer "github.com/vinihss/aiqfome/internal/interfaces/http/customer"
+	http_interfaces_favorite "github.com/vinihss/aiqfome/internal/interfaces/http/favorite"
+	http_interfaces_health "github.com/vinihss/aiqfome/internal/interfaces/http/health"
+	http_interfaces_product "github.com/vinihss/aiqfome/internal/interfaces/http/product"
+	"github.com/vinihss/aiqfome/internal/lifecycle"
+	"github.com/vinihss/aiqfome/internal/routes"
+	alertuse "github.com/vinihss/aiqfome/internal/usecases/alert"
+	authuse "github.com/vinihss/aiqfome/internal/usecases/authentication"
+	customeruse "github.com/vinihss/aiqfome/internal/usecases/customer"
+	favoriteuse "github.com/vinihss/aiqfome/internal/usecases/favorite"
+	productuse "github.com/vinihss/aiqfome/internal/usecases/product"
+	"github.com/vinihss/aiqfome/middlewares"
+)
+
+// App é a raiz de composição do serviço: cria cada dependência uma única vez
+// e as repassa para o registro de rotas. Qualquer dependência pode ser
+// substituída via Option, o que permite montar a aplicação inteira em testes
+// com repositórios em memória ou um catálogo de produtos falso.
+type App struct {
+	Config    *config.AppConfig
+	Lifecycle *lifecycle.Registry
+	Health    *health.Registry
+
+	DB    *gorm.DB
+	Redis *redis.Client
+
+	ProductProvider      productdomain.Provider
+	ProductCatalog       productdomain.Catalog
+	ProductCache         *external_epis.TieredProductCache
+	FavoriteRepository   favoritedomain.Repository
+	FavoriteSnapshots    favoritedomain.SnapshotRepository
+	PriceHistory         productdomain.PriceHistoryRepository
+	AlertRepository      alertdomain.Repository
+	AlertSenders         []alertdomain.Sender
+	CustomerRepository   customeruse.CustomerRepository
+	CredentialRepository authdomain.Repository
+	APIKeyRepository     authdomain.APIKeyRepository
+	SigningKeys          *signing.KeySet
+	RefreshTokens        authdomain.RefreshTokenStore
+	TokenDenylist        authdomain.TokenDenylist
+	APIKeys              *authuse.APIKeyUseCase
+	RateLimiter          middlewares.RateLimiter
+	FavoriteSync         *favoriteuse.SyncSnapshotsUseCase
+
+	Handlers routes.Handlers
+}
+
+// healthCheckTimeout limita cada verificação do /readyz.
+const healthCheckTimeout = 2 * time.Second
+
+type Option func(*App)
+
+func WithDB(db *gorm.DB) Option {
+	return func(a *App) { a.DB = db }
+}
+
+func WithRedis(rdb *redis.Client) Option {
+	return func(a *App) { a.Redis = rdb }
+}
+
+// WithProductProvider troca a fonte dos produtos mantendo o cache e o
+// circuit breaker; WithProductCatalog substitui o catálogo inteiro.
+func WithProductProvider(provider productdomain.Provider) Option {
+	return func(a *App) { a.ProductProvider = provider }
+}
+
+func WithProductCatalog(catalog productdomain.Catalog) Option {
+	return func(a *App) { a.ProductCatalog = catalog }
+}
+
+func WithProductCache(cache *external_epis.TieredProductCache) Option {
+	return func(a *App) { a.ProductCache = cache }
+}
+
+func WithFavoriteRepository(repo favoritedomain.Repository) Option {
+	return func(a *App) { a.FavoriteRepository = repo }
+}
+
+// WithFavoriteSnapshotRepository define o repositório usado pela
+// sincronização dos favoritos. Sem ele, é usado o FavoriteRepository quando
+// este também implementa SnapshotRepository.
+func WithFavoriteSnapshotRepository(repo favoritedomain.SnapshotRepository) Option {
+	return func(a *App) { a.FavoriteSnapshots = repo }
+}
+
+func WithPriceHistoryRepository(repo productdomain.PriceHistoryRepository) Option {
+	return func(a *App) { a.PriceHistory = repo }
+}
+
+func WithAlertRepository(repo alertdomain.Repository) Option {
+	return func(a *App) { a.AlertRepository = repo }
+}
+
+// WithAlertSenders substitui os canais de entrega dos alertas de preço.
+func WithAlertSenders(senders ...alertdomain.Sender) Option {
+	return func(a *App) { a.AlertSenders = senders }
+}
+
+func WithCredentialRepository(repo authdomain.Repository) Option {
+	return func(a *App) { a.CredentialRepository = repo }
+}
+
+func WithAPIKeyRepository(repo authdomain.APIKeyRepository) Option {
+	return func(a *App) { a.APIKeyRepository = repo }
+}
+
+func WithRateLimiter(limiter middlewares.RateLimiter) Option {
+	return func(a *App) { a.RateLimiter = limiter }
+}
+
+func WithSigningKeys(keys *signing.KeySet) Option {
+	return func(a *App) { a.SigningKeys = keys }
+}
+
+func WithRefreshTokenStore(store authdomain.RefreshTokenStore) Option {
+	return func(a *App) { a.RefreshTokens = store }
+}
+
+func WithTokenDenylist(denylist authdomain.TokenDenylist) Option {
+	return func(a *App) { a.TokenDenylist = denylist }
+}
+
+func WithCustomerRepository(repo customeruse.CustomerRepository) Option {
+	return func(a *App) { a.CustomerRepository = repo }
+}
+
+// New monta a aplicação. Conexões criadas aqui (e não recebidas via Option)
+// são registradas no Lifecycle para serem fechadas no encerramento.
+func New(cfg *config.AppConfig, opts ...Option) (*App, error) {
+	a := &App{
+		Config:    cfg,
+		Lifecycle: lifecycle.NewRegistry(),
+		Health:    health.NewRegistry(healthCheckTimeout),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if err := a.loadSigningKeys(); err != nil {
+		return nil, err
+	}
+	if err := a.connect(); err != nil {
+		return nil, errors.Join(err, a.Lifecycle.Stop(context.Background()))
+	}
+	if err := a.wireProducts(); err != nil {
+		return nil, errors.Join(err, a.Lifecycle.Stop(context.Background()))
+	}
+	if err := a.openAlertSenders(); err != nil {
+		return nil, errors.Join(err, a.Lifecycle.Stop(context.Background()))
+	}
+	a.wire()
+
+	return a, nil
+}
+
+func (a *App) loadSigningKeys() error {
+	if a.SigningKeys != nil {
+		return nil
+	}
+
+	jwtCfg := a.Config.JWT
+	if jwtCfg.SigningKeyFile == "" {
+		log.Printf("JWT_SIGNING_KEY_FILE not set, using an ephemeral signing key; tokens will not survive restarts")
+		keys, err := signing.GenerateEphemeral()
+		a.SigningKeys = keys
+		return err
+	}
+
+	keys, err := signing.LoadKeySet(jwtCfg.SigningKeyID, jwtCfg.SigningKeyFile, jwtCfg.VerificationKeys)
+	if err != nil {
+		return fmt.Errorf("loading JWT keys: %w", err)
+	}
+	a.SigningKeys = keys
+	return nil
+}
+
+func (a *App) connect() error {
+	needsRedis := a.FavoriteRepository == nil || a.RefreshTokens == nil || a.TokenDenylist == nil ||
+		(a.Config.RateLimit.Enabled && a.RateLimiter == nil) || a.ProductCache == nil
+	needsDB := a.FavoriteRepository == nil || a.CustomerRepository == nil ||
+		a.CredentialRepository == nil || a.APIKeyRepository == nil || a.PriceHistory == nil || a.AlertRepository == nil
+
+	if a.Redis == nil && needsRedis {
+		rdb := config.NewRedisClient(a.Config.Redis)
+		a.Redis = rdb
+		a.Lifecycle.Append(lifecycle.Hook{
+			Name: "redis",
+			OnStop: func(context.Context) error {
+				return rdb.Close()
+			},
+		})
+	}
+
+	if a.DB == nil && needsDB {
+		db, err := config.ConnectDB(a.Config.Database)
+		if err != nil {
+			return err
+		}
+		a.DB = db
+		a.Lifecycle.Append(lifecycle.Hook{
+			Name: "postgres",
+			OnStop: func(context.Context) error {
+				sqlDB, err := db.DB()
+				if err != nil {
+					return err
+				}
+				return sqlDB.Close()
+			},
+		})
+	}
+
+	return nil
+}
+
+// wireProducts monta o catálogo de produtos: o provider escolhido em
+// PRODUCT_PROVIDER decorado com o cache em dois níveis e o circuit breaker.
+func (a *App) wireProducts() error {
+	productCfg := a.Config.Product
+	if a.ProductCache == nil {
+		a.ProductCache = external_epis.NewTieredProductCache(a.Redis, external_epis.CacheOptions{
+			TTL:         productCfg.CacheTTL,
+			NotFoundTTL: productCfg.CacheNotFoundTTL,
+			StaleTTL:    productCfg.CacheStaleTTL,
+			MaxEntries:  productCfg.CacheMaxEntries,
+		})
+	}
+	a.Lifecycle.Append(lifecycle.Background("product-cache-invalidation", a.ProductCache.Listen))
+	a.Lifecycle.Append(lifecycle.Background("product-cache-janitor", a.ProductCache.RunJanitor))
+	if a.ProductCatalog != nil {
+		return nil
+	}
+
+	retry := httpretry.DefaultPolicy()
+	retry.MaxAttempts = productCfg.RetryMaxAttempts
+	retry.BaseDelay = productCfg.RetryBaseDelay
+	retry.MaxDelay = productCfg.RetryMaxDelay
+	if productCfg.RetryStatuses != nil {
+		retry.RetryableStatus = productCfg.RetryStatuses
+	}
+	if a.ProductProvider == nil {
+		provider, err := external_epis.NewProvider(productCfg.Provider, external_epis.ProviderConfig{
+			BaseURL:     productCfg.BaseURL,
+			Token:       productCfg.ServiceToken,
+			CatalogFile: productCfg.CatalogFile,
+			Timeout:     productCfg.Timeout,
+			Retry:       retry,
+		})
+		if err != nil {
+			return fmt.Errorf("creating product provider: %w", err)
+		}
+		a.ProductProvider = provider
+	}
+
+	breaker := external_epis.NewCircuitBreaker(external_epis.BreakerSettings{
+		FailureThreshold:  productCfg.BreakerFailures,
+		Window:            productCfg.BreakerWindow,
+		CooldownPeriod:    productCfg.BreakerCooldown,
+		HalfOpenMaxProbes: productCfg.BreakerHalfOpenProbes,
+		OnStateChange: func(from, to string) {
+			log.Printf("product catalog circuit breaker (%s): %s -> %s", productCfg.Provider, from, to)
+		},
+	})
+	a.ProductCatalog = external_epis.NewCachedCatalog(a.ProductProvider, a.ProductCache, breaker, retry.MaxDuration(productCfg.Timeout))
+	return nil
+}
+
+// openAlertSenders cria o sender de log dos alertas de preço, gravando em
+// ALERTS_LOG_FILE ou no log do processo.
+func (a *App) openAlertSenders() error {
+	if len(a.AlertSenders) > 0 {
+		return nil
+	}
+	if a.Config.Alerts.LogFile == "" {
+		a.AlertSenders = []alertdomain.Sender{notifications.NewLogSender(log.Writer())}
+		return nil
+	}
+
+	f, err := os.OpenFile(a.Config.Alerts.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening alerts log file: %w", err)
+	}
+	a.Lifecycle.Append(lifecycle.Hook{
+		Name: "alerts-log-file",
+		OnStop: func(context.Context) error {
+			return f.Close()
+		},
+	})
+	a.AlertSenders = []alertdomain.Sender{notifications.NewLogSender(f)}
+	return nil
+}
+
+func (a *App) wire() {
+	if a.FavoriteRepository == nil {
+		a.FavoriteRepository = repositories.NewFavoriteRepository(a.DB, a.Redis)
+	}
+	if a.FavoriteSnapshots == nil {
+		a.FavoriteSnapshots, _ = a.FavoriteRepository.(favoritedomain.SnapshotRepository)
+	}
+	if a.PriceHistory == nil {
+		a.PriceHistory = repositories.NewPriceHistoryRepository(a.DB)
+	}
+	if a.AlertRepository == nil {
+		a.AlertRepository = repositories.NewAlertRepository(a.DB)
+	}
+	if a.CustomerRepository == nil {
+		a.CustomerRepository = repositories.NewCustomerRepository(a.DB)
+	}
+	if a.CredentialRepository == nil {
+		a.CredentialRepository = repositories.NewCredentialRepository(a.DB)
+	}
+	if a.APIKeyRepository == nil {
+		a.APIKeyRepository = repositories.NewAPIKeyRepository(a.DB)
+	}
+	if a.RefreshTokens == nil {
+		a.RefreshTokens = sessions.NewRefreshTokenStore(a.Redis)
+	}
+	if a.TokenDenylist == nil {
+		a.TokenDenylist = sessions.NewTokenDenylist(a.Redis)
+	}
+	if a.RateLimiter == nil && a.Config.RateLimit.Enabled {
+		a.RateLimiter = ratelimit.NewRedisLimiter(a.Redis)
+	}
+
+	a.registerHealthCheckers()
+	a.Handlers.Health = http_interfaces_health.NewHealthHandler(a.Health)
+
+	authenticateUC := authuse.NewAuthenticateUseCase(a.CredentialRepository)
+	registerUC := authuse.NewRegisterUseCase(a.CredentialRepository)
+	refreshUC := authuse.NewRefreshUseCase(a.RefreshTokens, a.Config.JWT.RefreshTTL)
+	logoutUC := authuse.NewLogoutUseCase(a.TokenDenylist, refreshUC)
+	authController := http_interfaces_authentication.NewAuthenticationController(authenticateUC, registerUC, refreshUC, logoutUC, a.SigningKeys, a.Config.JWT)
+	a.Handlers.Authentication = http_interfaces_authentication.NewAuthenticationHandler(authController)
+
+	a.APIKeys = authuse.NewAPIKeyUseCase(a.APIKeyRepository)
+	a.Handlers.APIKey = http_interfaces_apikey.NewAPIKeyHandler(http_interfaces_apikey.NewAPIKeyController(a.APIKeys))
+
+	createFavoriteUC := favoriteuse.NewAddFavoriteUseCase(a.FavoriteRepository, a.ProductCatalog, a.PriceHistory)
+	listFavoriteUC := favoriteuse.NewListFavoritesUseCase(a.FavoriteRepository)
+	removeFavoriteUC := favoriteuse.NewRemoveFavoriteUseCase(a.FavoriteRepository)
+	priceHistoryUC := favoriteuse.NewPriceHistoryUseCase(a.FavoriteRepository, a.PriceHistory)
+	favController := http_interfaces_favorite.NewFavoriteController(createFavoriteUC, listFavoriteUC, removeFavoriteUC, priceHistoryUC)
+	a.Handlers.Favorite = http_interfaces_favorite.NewFavoriteHandler(favController)
+
+	alertDetector := alertuse.NewDetector(a.AlertRepository, a.AlertSenders...)
+	alertController := http_interfaces_alert.NewAlertController(
+		alertuse.NewRuleUseCase(a.AlertRepository, a.FavoriteRepository),
+		alertuse.NewFeedUseCase(a.AlertRepository))
+	a.Handlers.Alert = http_interfaces_alert.NewAlertHandler(alertController)
+
+	if a.FavoriteSnapshots != nil {
+		syncCfg := a.Config.FavoritesSync
+		a.FavoriteSync = favoriteuse.NewSyncSnapshotsUseCase(a.FavoriteSnapshots, a.ProductCatalog, a.PriceHistory, alertDetector, favoriteuse.SyncOptions{
+			MaxAge:        syncCfg.MaxAge,
+			BatchSize:     syncCfg.BatchSize,
+			RatePerSecond: syncCfg.RatePerSecond,
+		})
+		if syncCfg.Enabled {
+			a.Lifecycle.Append(lifecycle.Background("favorites-sync", func(ctx context.Context) error {
+				return a.FavoriteSync.Run(ctx, syncCfg.Interval)
+			}))
+		}
+	}
+
+	purgeCacheUC := productuse.NewPurgeCacheUseCase(a.ProductCache)
+	a.Handlers.Product = http_interfaces_product.NewProductHandler(http_interfaces_product.NewProductController(purgeCacheUC))
+
+	createCustomerUC := customeruse.NewCreateCustomerUseCase(a.CustomerRepository)
+	deleteCustomerUC := customeruse.NewDeleteCustomerUseCase(a.CustomerRepository)
+	findCustomerUC := customeruse.NewFindCustomerUseCase(a.CustomerRepository)
+	updateCustomerUC := customeruse.NewUpdateCustomerUseCase(a.CustomerRepository)
+	custController := http_interfaces_customer.NewCustomerController(createCustomerUC, deleteCustomerUC, findCustomerUC, updateCustomerUC)
+	a.Handlers.Customer = http_interfaces_customer.NewCustomerHandler(custController)
+}
+
+func (a *App) registerHealthCheckers() {
+	if a.DB != nil {
+		a.Health.Register(health.NewDBChecker(a.DB))
+	}
+	if a.Redis != nil {
+		a.Health.Register(health.NewRedisChecker(a.Redis))
+	}
+	if favorites, ok := a.FavoriteRepository.(*repositories.FavoriteRepository); ok && a.Redis != nil {
+		a.Health.Register(health.NewCacheStatsChecker("favorites_cache", func() (hits, misses, errors uint64) {
+			stats := favorites.CacheStats()
+			return stats.Hits, stats.Misses, stats.Errors
+		}))
+	}
+	if catalog, ok := a.ProductCatalog.(interface {
+		CircuitBreaker() *external_epis.CircuitBreaker
+	}); ok {
+		a.Health.Register(health.NewCircuitBreakerChecker("product_api", catalog.CircuitBreaker()))
+	}
+}
+
+// Router cria o engine Gin com todas as rotas registradas.
+func (a *App) Router() *gin.Engine {
+	r := newEngine(a.Config.Server)
+	auth := middlewares.Authenticate(
+		middlewares.JWTAuth(a.Config.JWT, a.SigningKeys, a.TokenDenylist),
+		middlewares.APIKeyAuth(a.APIKeys),
+	)
+	routes.SetupRoutes(r, auth, a.rateLimits(), a.Handlers)
+	return r
+}
+
+// newEngine cria o engine confiando em X-Forwarded-For apenas quando a
+// conexão vem de um dos proxies configurados. Sem isso o Gin aceitaria o
+// header de qualquer cliente, que trocaria de IP a cada requisição para
+// escapar do rate limit das rotas públicas.
+func newEngine(cfg config.ServerConfig) *gin.Engine {
+	r := gin.Default()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Printf("invalid trusted proxies %v, trusting none: %v", cfg.TrustedProxies, err)
+		_ = r.SetTrustedProxies(nil)
+	}
+	return r
+}
+
+func (a *App) rateLimits() routes.RateLimits {
+	cfg := a.Config.RateLimit
+	limit := func(group string, l config.RateLimit) gin.HandlerFunc {
+		if !cfg.Enabled {
+			return func(c *gin.Context) { c.Next() }
+		}
+		return middlewares.RateLimit(a.RateLimiter, group, l)
+	}
+	return routes.RateLimits{
+		Public:         limit("public", cfg.Public),
+		API:            limit("api", cfg.API),
+		FavoritesWrite: limit("favorites_write", cfg.FavoritesWrite),
+	}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vinihss/aiqfome/config"
+	"github.com/vinihss/aiqfome/internal/infrastructure/ratelimit"
+	"github.com/vinihss/aiqfome/middlewares"
+)
+
+// keyRecorder aceita todas as requisições e guarda as chaves consultadas.
+type keyRecorder struct {
+	mutex sync.Mutex
+	keys  []string
+}
+
+func (r *keyRecorder) Allow(_ context.Context, key string, requests int, _ time.Duration) (ratelimit.Result, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.keys = append(r.keys, key)
+	return ratelimit.Result{Allowed: true, Limit: requests, Remaining: requests}, nil
+}
+
+func publicRequestKeys(t *testing.T, server config.ServerConfig, remoteAddr string, forwardedFor ...string) []string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	limiter := &keyRecorder{}
+	r := newEngine(server)
+	r.POST("/authenticate", middlewares.RateLimit(limiter, "public", config.RateLimit{Requests: 20, Window: time.Minute}),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, ip := range forwardedFor {
+		req := httptest.NewRequest(http.MethodPost, "/authenticate", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", ip)
+		req.Header.Set("X-Real-IP", ip)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	return limiter.keys
+}
+
+func TestSpoofedForwardedForDoesNotChangeRateLimitKey(t *testing.T) {
+	keys := publicRequestKeys(t, config.ServerConfig{}, "198.51.100.7:40000", "203.0.113.1", "203.0.113.2", "10.0.0.1")
+
+	for _, key := range keys {
+		if key != "public:ip:198.51.100.7" {
+			t.Errorf("key = %q, want the connection address", key)
+		}
+	}
+	if len(keys) != 3 {
+		t.Errorf("limiter calls = %d, want 3", len(keys))
+	}
+}
+
+func TestForwardedForFromTrustedProxyIsUsed(t *testing.T) {
+	server := config.ServerConfig{TrustedProxies: []string{"10.0.0.0/8"}}
+
+	if keys := publicRequestKeys(t, server, "10.1.2.3:40000", "203.0.113.1"); len(keys) != 1 || keys[0] != "public:ip:203.0.113.1" {
+		t.Errorf("keys behind a trusted proxy = %v, want the forwarded client", keys)
+	}
+	// Fora da lista, o header continua ignorado.
+	if keys := publicRequestKeys(t, server, "198.51.100.7:40000", "203.0.113.1"); len(keys) != 1 || keys[0] != "public:ip:198.51.100.7" {
+		t.Errorf("keys from an untrusted peer = %v, want the connection address", keys)
+	}
+}
@@ -0,0 +1,78 @@
+package alert
+
+import "time"
+
+// Tipos de regra de alerta de preço.
+const (
+	// KindAnyDrop dispara a cada queda em relação ao último preço observado.
+	KindAnyDrop = "any_drop"
+	// KindBelowPrice dispara quando o preço fica abaixo de Threshold.
+	KindBelowPrice = "below_price"
+	// KindDropPercent dispara quando o preço cai Threshold por cento ou mais
+	// em relação a ReferencePrice.
+	KindDropPercent = "drop_percent"
+)
+
+func IsValidKind(kind string) bool {
+	switch kind {
+	case KindAnyDrop, KindBelowPrice, KindDropPercent:
+		return true
+	}
+	return false
+}
+
+// Rule é a inscrição de um cliente em alertas de preço de um favorito.
+type Rule struct {
+	ID         uint
+	CustomerID uint
+	ProductID  uint
+	Kind       string
+	Threshold  float32
+	// ReferencePrice é o preço quando a regra foi criada, base de
+	// KindDropPercent.
+	ReferencePrice float32
+	// LastPrice é o último preço avaliado, base de KindAnyDrop.
+	LastPrice float32
+	// Triggered indica que a condição já foi atingida e o alerta emitido; a
+	// regra só volta a disparar depois que a condição deixar de valer.
+	Triggered bool
+	// Version é incrementada a cada avaliação, para que duas avaliações
+	// concorrentes não emitam o mesmo alerta.
+	Version   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Evaluate aplica um novo preço à regra e devolve o estado seguinte e se um
+// alerta deve ser emitido. Cada cruzamento do limite dispara uma única vez.
+func (r Rule) Evaluate(price float32) (next Rule, fired bool) {
+	next = r
+	next.LastPrice = price
+
+	switch r.Kind {
+	case KindAnyDrop:
+		fired = price < r.LastPrice
+	case KindBelowPrice:
+		next.Triggered = price < r.Threshold
+		fired = next.Triggered && !r.Triggered
+	case KindDropPercent:
+		// Sem preço de referência não há queda a medir.
+		next.Triggered = r.ReferencePrice > 0 && price <= r.ReferencePrice*(1-r.Threshold/100)
+		fired = next.Triggered && !r.Triggered
+	}
+	return next, fired
+}
+
+// Alert é um alerta emitido por uma regra.
+type Alert struct {
+	ID             uint
+	CustomerID     uint
+	ProductID      uint
+	RuleID         uint
+	Kind           string
+	Threshold      float32
+	OldPrice       float32
+	NewPrice       float32
+	CreatedAt      time.Time
+	AcknowledgedAt *time.Time
+}
@@ -0,0 +1,49 @@
+package alert
+
+import "testing"
+
+func TestRuleEvaluate(t *testing.T) {
+	anyDrop := Rule{Kind: KindAnyDrop, LastPrice: 100}
+	below := Rule{Kind: KindBelowPrice, Threshold: 80, LastPrice: 100}
+	dropPercent := Rule{Kind: KindDropPercent, Threshold: 10, ReferencePrice: 100, LastPrice: 100}
+
+	// Cada passo parte do estado devolvido pelo anterior.
+	tests := []struct {
+		name       string
+		rule       Rule
+		prices     []float32
+		wantFired  []bool
+		wantActive bool // Triggered depois do último preço
+	}{
+		{"any_drop ignores an equal price", anyDrop, []float32{100}, []bool{false}, false},
+		{"any_drop ignores a rising price", anyDrop, []float32{120}, []bool{false}, false},
+		{"any_drop fires on every drop", anyDrop, []float32{90, 90, 85, 95, 94}, []bool{true, false, true, false, true}, false},
+		{"below_price fires once while below", below, []float32{79, 70, 75}, []bool{true, false, false}, true},
+		{"below_price at the threshold is not below", below, []float32{80}, []bool{false}, false},
+		{"below_price fires again after going back above", below, []float32{79, 85, 78}, []bool{true, false, true}, true},
+		{"drop_percent fires exactly at the boundary", dropPercent, []float32{90}, []bool{true}, true},
+		{"drop_percent just above the boundary", dropPercent, []float32{90.01}, []bool{false}, false},
+		{"drop_percent fires once per crossing", dropPercent, []float32{89, 80, 95, 90}, []bool{true, false, false, true}, true},
+		{"drop_percent with a fractional boundary", Rule{Kind: KindDropPercent, Threshold: 20, ReferencePrice: 109.95}, []float32{87.96, 87.97}, []bool{true, false}, false},
+		{"drop_percent without a reference price", Rule{Kind: KindDropPercent, Threshold: 10}, []float32{50, 0}, []bool{false, false}, false},
+		{"unknown kind never fires", Rule{Kind: "other", LastPrice: 100}, []float32{1}, []bool{false}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			for i, price := range tt.prices {
+				next, fired := rule.Evaluate(price)
+				if fired != tt.wantFired[i] {
+					t.Errorf("price %v (step %d): fired = %v, want %v", price, i, fired, tt.wantFired[i])
+				}
+				if next.LastPrice != price {
+					t.Errorf("price %v (step %d): LastPrice = %v", price, i, next.LastPrice)
+				}
+				rule = next
+			}
+			if rule.Triggered != tt.wantActive {
+				t.Errorf("Triggered = %v, want %v", rule.Triggered, tt.wantActive)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound é devolvido pelo repositório quando a regra ou o alerta não
+// existe para o cliente informado.
+var ErrNotFound = errors.New("regra ou alerta não encontrado")
+
+type Repository interface {
+	// SaveRule cria ou substitui a regra do favorito (CustomerID, ProductID).
+	SaveRule(r Rule) (Rule, error)
+	FindRule(customerID, productID uint) (Rule, error)
+	DeleteRule(customerID, productID uint) error
+	RulesForProduct(productID uint) ([]Rule, error)
+	// ApplyEvaluation grava next se a regra ainda estiver na versão de
+	// current e, na mesma transação, cria alert quando não for nil. applied é
+	// falso se outra avaliação gravou a regra antes.
+	ApplyEvaluation(current, next Rule, alert *Alert) (stored *Alert, applied bool, err error)
+
+	ListAlerts(customerID uint, unacknowledgedOnly bool, limit int) ([]Alert, error)
+	Acknowledge(customerID, alertID uint, at time.Time) (Alert, error)
+}
+
+// Sender entrega um alerta ao cliente por algum canal (e-mail, push,
+// webhook). Falhas de entrega não desfazem o alerta, que continua no feed.
+type Sender interface {
+	Name() string
+	Send(ctx context.Context, a Alert) error
+}
@@ -0,0 +1,31 @@
+package authentication
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrAPIKeyNotFound é devolvido pelo repositório quando nenhuma chave tem o
+// hash ou o ID informado.
+var ErrAPIKeyNotFound = errors.New("API key não encontrada")
+
+type APIKey struct {
+	ID         uint
+	Name       string
+	Prefix     string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// APIKeyRepository guarda apenas o hash das chaves; o valor em texto só é
+// conhecido no momento da criação.
+type APIKeyRepository interface {
+	Create(key APIKey, keyHash string) (APIKey, error)
+	FindByHash(keyHash string) (APIKey, error)
+	List() ([]APIKey, error)
+	Revoke(id uint, at time.Time) error
+	TouchLastUsed(id uint, at time.Time) error
+}
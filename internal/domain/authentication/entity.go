@@ -0,0 +1,20 @@
+package authentication
+
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+)
+
+type Credential struct {
+	CustomerID   uint
+	Email        string
+	PasswordHash string
+	Roles        []string
+}
+
+// Identity é o resultado de uma autenticação bem-sucedida e vira as claims
+// do token emitido.
+type Identity struct {
+	CustomerID uint
+	Roles      []string
+}
@@ -0,0 +1,68 @@
+package authentication
+
+import "slices"
+
+const (
+	ScopeFavoritesRead  = "favorites:read"
+	ScopeFavoritesWrite = "favorites:write"
+	ScopeCustomersRead  = "customers:read"
+	ScopeCustomersWrite = "customers:write"
+	ScopeAPIKeysManage  = "api_keys:manage"
+	ScopeProductsManage = "products:manage"
+)
+
+// Scopes lista todos os escopos reconhecidos pela API.
+var Scopes = []string{
+	ScopeFavoritesRead,
+	ScopeFavoritesWrite,
+	ScopeCustomersRead,
+	ScopeCustomersWrite,
+	ScopeAPIKeysManage,
+	ScopeProductsManage,
+}
+
+var roleScopes = map[string][]string{
+	RoleCustomer: {ScopeFavoritesRead, ScopeFavoritesWrite, ScopeCustomersRead, ScopeCustomersWrite},
+	RoleAdmin:    Scopes,
+}
+
+func IsValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// ScopesForRoles converte os papéis de um cliente nos escopos equivalentes,
+// para que tokens de cliente e API keys sejam autorizados da mesma forma.
+func ScopesForRoles(roles []string) []string {
+	var out []string
+	for _, role := range roles {
+		for _, scope := range roleScopes[role] {
+			if !slices.Contains(out, scope) {
+				out = append(out, scope)
+			}
+		}
+	}
+	return out
+}
+
+const (
+	PrincipalCustomer = "customer"
+	PrincipalAPIKey   = "api_key"
+)
+
+// Principal é quem está fazendo a requisição: um cliente autenticado por JWT
+// ou um sistema interno autenticado por API key.
+type Principal struct {
+	Kind       string
+	CustomerID uint
+	APIKeyID   uint
+	Roles      []string
+	Scopes     []string
+}
+
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
@@ -0,0 +1,17 @@
+package authentication
+
+import (
+	"errors"
+
+	"github.com/vinihss/aiqfome/internal/domain/customer"
+)
+
+// ErrCredentialNotFound é devolvido por FindByEmail quando não há cliente
+// com o email informado.
+var ErrCredentialNotFound = errors.New("credencial não encontrada")
+
+type Repository interface {
+	// Register cria o cliente e sua credencial na mesma transação.
+	Register(c customer.Customer, passwordHash string, roles []string) (customer.Customer, error)
+	FindByEmail(email string) (Credential, error)
+}
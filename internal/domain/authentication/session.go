@@ -0,0 +1,35 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrRefreshSessionNotFound = errors.New("refresh token não encontrado")
+
+// RefreshSession é o estado associado a um refresh token. Todos os tokens
+// obtidos por rotação a partir do mesmo login compartilham o FamilyID.
+type RefreshSession struct {
+	FamilyID   string    `json:"family_id"`
+	CustomerID uint      `json:"customer_id"`
+	Roles      []string  `json:"roles"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// RefreshTokenStore guarda refresh tokens pelo hash, nunca pelo valor. Find
+// devolve ErrRefreshSessionNotFound para tokens desconhecidos ou expirados.
+type RefreshTokenStore interface {
+	Save(ctx context.Context, tokenHash string, session RefreshSession) error
+	Find(ctx context.Context, tokenHash string) (RefreshSession, error)
+	// MarkUsed devolve false se o token já havia sido usado antes.
+	MarkUsed(ctx context.Context, tokenHash string, until time.Time) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string, until time.Time) error
+	IsFamilyRevoked(ctx context.Context, familyID string) (bool, error)
+}
+
+// TokenDenylist guarda os jti de access tokens revogados até expirarem.
+type TokenDenylist interface {
+	Revoke(ctx context.Context, jti string, until time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
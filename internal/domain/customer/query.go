@@ -0,0 +1,75 @@
+package customer
+
+import "errors"
+
+// Ordenações aceitas na listagem de clientes.
+const (
+	SortID    = "id"
+	SortName  = "name"
+	SortEmail = "email"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("cursor inválido para esta listagem")
+	ErrInvalidQuery  = errors.New("parâmetros de listagem inválidos")
+)
+
+func IsValidSort(sort string) bool {
+	switch sort {
+	case SortID, SortName, SortEmail:
+		return true
+	}
+	return false
+}
+
+// ListQuery descreve uma página da listagem de clientes.
+type ListQuery struct {
+	// Page começa em 1. Sem Cursor, a página é lida por offset.
+	Page int
+	Size int
+	// Cursor é o NextCursor da página anterior. Quando presente, a página é
+	// lida por keyset a partir dele, sem percorrer as linhas anteriores.
+	Cursor string
+	Sort   string
+	Desc   bool
+
+	// Search filtra pelo prefixo do nome ou do e-mail, sem diferenciar
+	// maiúsculas.
+	Search string
+	// Email filtra pelo e-mail exato, sem diferenciar maiúsculas.
+	Email string
+}
+
+// WithDefaults preenche os campos omitidos: página 1, 20 clientes, por id.
+func (q ListQuery) WithDefaults() ListQuery {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.Size == 0 {
+		q.Size = DefaultPageSize
+	}
+	if q.Sort == "" {
+		q.Sort = SortID
+	}
+	return q
+}
+
+func (q ListQuery) Validate() error {
+	if q.Page < 1 || q.Size < 1 || q.Size > MaxPageSize || !IsValidSort(q.Sort) {
+		return ErrInvalidQuery
+	}
+	return nil
+}
+
+// Page é uma página da listagem. Total conta todos os clientes que atendem
+// aos filtros; NextCursor fica vazio na última página.
+type Page struct {
+	Items      []Customer
+	Total      int64
+	NextCursor string
+}
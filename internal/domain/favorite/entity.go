@@ -1,10 +1,39 @@
 package favorite
 
+import "time"
+
 type Favorite struct {
 	ID         uint
 	CustomerID uint
 	ProductID  uint
 	Title      string
 	ImageUrl   string
-	Price      float32
+	Category   string
+	// Price é o preço atual, mantido pela sincronização; FavoritedPrice, o
+	// preço no momento em que o produto foi favoritado.
+	Price          float32
+	FavoritedPrice float32
+	// Available fica falso quando o produto deixa de existir no catálogo;
+	// o favorito é mantido com os últimos dados conhecidos.
+	Available    bool
+	LastSyncedAt *time.Time
+	CreatedAt    time.Time
+}
+
+// PriceChangePercent é a variação percentual do preço desde que o produto foi
+// favoritado, negativa quando o produto ficou mais barato.
+func (f Favorite) PriceChangePercent() float64 {
+	if f.FavoritedPrice == 0 {
+		return 0
+	}
+	return float64(f.Price-f.FavoritedPrice) / float64(f.FavoritedPrice) * 100
+}
+
+// Snapshot são os dados do produto copiados para cada favorito.
+type Snapshot struct {
+	ProductID uint
+	Title     string
+	ImageUrl  string
+	Category  string
+	Price     float32
 }
@@ -0,0 +1,53 @@
+package favorite
+
+import "errors"
+
+// Ordenações aceitas na listagem de favoritos.
+const (
+	SortCreatedAt = "created_at"
+	SortPrice     = "price"
+	SortTitle     = "title"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("cursor inválido para esta listagem")
+	ErrInvalidQuery  = errors.New("parâmetros de listagem inválidos")
+)
+
+func IsValidSort(sort string) bool {
+	switch sort {
+	case SortCreatedAt, SortPrice, SortTitle:
+		return true
+	}
+	return false
+}
+
+// ListQuery descreve uma página da listagem de favoritos de um cliente.
+type ListQuery struct {
+	CustomerID uint
+	Limit      int
+	// Cursor é o valor opaco devolvido em Page.NextCursor; vazio pede a
+	// primeira página. Só é válido com a mesma ordenação que o gerou.
+	Cursor string
+	Sort   string
+	Desc   bool
+
+	MinPrice *float32
+	MaxPrice *float32
+	// Search filtra pelo título, sem diferenciar maiúsculas.
+	Search   string
+	Category string
+}
+
+// Page é uma página da listagem. NextCursor fica vazio na última página e
+// Total conta todos os favoritos que atendem aos filtros.
+type Page struct {
+	Items      []Favorite
+	NextCursor string
+	Total      int64
+}
@@ -1,8 +1,37 @@
 package favorite
 
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound é devolvido pelo repositório quando o cliente não tem o produto
+// nos favoritos.
+var ErrNotFound = errors.New("favorito não encontrado")
+
 type Repository interface {
 	Create(f Favorite) (Favorite, error)
 	Exists(customerID uint, productID uint) (bool, error)
-	ListByCustomer(customerID uint) ([]Favorite, error)
+	Find(customerID uint, productID uint) (Favorite, error)
+	ListByCustomer(q ListQuery) (Page, error)
 	Delete(customerID uint, productID uint) error
 }
+
+// SnapshotRepository é usado pela sincronização periódica dos dados de
+// produto guardados nos favoritos.
+type SnapshotRepository interface {
+	// StaleProductIDs devolve até limit IDs de produto distintos, maiores que
+	// afterID e em ordem crescente, com algum favorito nunca sincronizado ou
+	// sincronizado antes de olderThan.
+	StaleProductIDs(afterID uint, olderThan time.Time, limit int) ([]uint, error)
+	// UpdateSnapshot grava os dados do produto em todos os seus favoritos e
+	// os marca como disponíveis.
+	UpdateSnapshot(s Snapshot, syncedAt time.Time) error
+	// MarkUnavailable marca os favoritos de um produto que não existe mais
+	// no catálogo, mantendo os últimos dados conhecidos.
+	MarkUnavailable(productID uint, syncedAt time.Time) error
+	// WithSyncLock executa fn apenas se nenhuma outra instância estiver
+	// sincronizando. acquired é falso se o lock já estava em uso.
+	WithSyncLock(ctx context.Context, fn func(ctx context.Context) error) (acquired bool, err error)
+}
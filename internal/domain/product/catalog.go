@@ -0,0 +1,26 @@
+package product
+
+import "context"
+
+// Catalog é a porta pela qual o serviço consulta produtos, independente de
+// qual provedor esteja configurado.
+type Catalog interface {
+	GetProduct(ctx context.Context, id uint) (Product, error)
+	// GetProducts busca vários produtos de uma vez. O mapa tem uma entrada
+	// para cada ID distinto recebido, com o produto ou o erro da consulta.
+	GetProducts(ctx context.Context, ids []uint) map[uint]Result
+}
+
+type Result struct {
+	Product Product
+	Err     error
+}
+
+// Provider é uma fonte de produtos sem cache nem circuit breaker; esses
+// comportamentos são aplicados por cima de qualquer provider.
+type Provider interface {
+	Fetch(ctx context.Context, id uint) (Product, error)
+	// FetchAll devolve o catálogo inteiro, ou ErrListUnsupported se o
+	// provider não oferecer listagem.
+	FetchAll(ctx context.Context) ([]Product, error)
+}
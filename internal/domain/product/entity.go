@@ -0,0 +1,21 @@
+package product
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("produto não encontrado no catálogo")
+	// ErrUnavailable indica que o catálogo está temporariamente fora, por
+	// exemplo com o circuit breaker aberto.
+	ErrUnavailable = errors.New("catálogo de produtos temporariamente indisponível")
+	// ErrListUnsupported é devolvido por providers que não oferecem a
+	// listagem completa do catálogo.
+	ErrListUnsupported = errors.New("provider não suporta listagem de produtos")
+)
+
+type Product struct {
+	ID       uint
+	Title    string
+	Image    string
+	Price    float32
+	Category string
+}
@@ -0,0 +1,19 @@
+package product
+
+import "time"
+
+// PricePoint é um preço observado no catálogo a partir de RecordedAt.
+type PricePoint struct {
+	ProductID  uint
+	Price      float32
+	RecordedAt time.Time
+}
+
+type PriceHistoryRepository interface {
+	// Record registra o preço observado, a menos que seja igual ao último
+	// registrado para o produto.
+	Record(productID uint, price float32, at time.Time) error
+	// History devolve os limit registros mais recentes do produto, do mais
+	// novo para o mais antigo.
+	History(productID uint, limit int) ([]PricePoint, error)
+}
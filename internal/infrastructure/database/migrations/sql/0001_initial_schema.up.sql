@@ -0,0 +1,21 @@
+-- Esquema criado até então pelo AutoMigrate do GORM. Os comandos usam
+-- IF NOT EXISTS para que bancos já existentes sejam adotados sem alterações.
+CREATE TABLE IF NOT EXISTS customers (
+    id    BIGSERIAL PRIMARY KEY,
+    name  TEXT,
+    email TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_email ON customers (email);
+
+CREATE TABLE IF NOT EXISTS favorites (
+    id          BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT  NOT NULL,
+    product_id  BIGINT  NOT NULL,
+    title       TEXT    NOT NULL,
+    image_url   TEXT    NOT NULL,
+    price       DECIMAL NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorites_customer_id ON favorites (customer_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_customer_product ON favorites (customer_id, product_id);
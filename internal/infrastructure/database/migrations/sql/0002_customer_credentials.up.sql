@@ -0,0 +1,6 @@
+CREATE TABLE customer_credentials (
+    customer_id   BIGINT PRIMARY KEY REFERENCES customers (id) ON DELETE CASCADE,
+    password_hash TEXT        NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1 @@
+ALTER TABLE customer_credentials DROP COLUMN roles;
@@ -0,0 +1 @@
+ALTER TABLE customer_credentials ADD COLUMN roles TEXT NOT NULL DEFAULT 'customer';
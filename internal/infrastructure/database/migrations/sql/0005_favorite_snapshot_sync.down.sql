@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_favorites_product_id;
+
+ALTER TABLE favorites
+    DROP COLUMN IF EXISTS available,
+    DROP COLUMN IF EXISTS last_synced_at;
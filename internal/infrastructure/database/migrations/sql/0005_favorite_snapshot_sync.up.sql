@@ -0,0 +1,8 @@
+-- Controle da atualização periódica dos dados do produto copiados para o
+-- favorito. Produtos que deixaram de existir no catálogo são mantidos com
+-- available = false em vez de removidos.
+ALTER TABLE favorites
+    ADD COLUMN last_synced_at TIMESTAMPTZ,
+    ADD COLUMN available      BOOLEAN NOT NULL DEFAULT true;
+
+CREATE INDEX idx_favorites_product_id ON favorites (product_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS product_price_history;
+
+ALTER TABLE favorites DROP COLUMN IF EXISTS favorited_price;
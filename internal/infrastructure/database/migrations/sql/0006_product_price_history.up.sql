@@ -0,0 +1,21 @@
+-- Preço no momento em que o produto foi favoritado. A coluna price passa a
+-- ser o preço atual, mantido pela sincronização dos favoritos.
+ALTER TABLE favorites ADD COLUMN favorited_price DECIMAL;
+UPDATE favorites SET favorited_price = price;
+ALTER TABLE favorites ALTER COLUMN favorited_price SET NOT NULL;
+
+-- Uma linha por mudança de preço observada no catálogo.
+CREATE TABLE product_price_history (
+    id          BIGSERIAL PRIMARY KEY,
+    product_id  BIGINT      NOT NULL,
+    price       DECIMAL     NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_product_price_history_product ON product_price_history (product_id, recorded_at DESC);
+
+-- O preço já guardado nos favoritos é o primeiro ponto do histórico.
+INSERT INTO product_price_history (product_id, price, recorded_at)
+SELECT DISTINCT ON (product_id) product_id, price, COALESCE(last_synced_at, now())
+FROM favorites
+ORDER BY product_id, last_synced_at DESC NULLS LAST;
@@ -0,0 +1,35 @@
+-- Uma regra de alerta por favorito; removida junto com o favorito.
+CREATE TABLE alert_rules (
+    id              BIGSERIAL PRIMARY KEY,
+    customer_id     BIGINT      NOT NULL,
+    product_id      BIGINT      NOT NULL,
+    kind            TEXT        NOT NULL,
+    threshold       DECIMAL     NOT NULL DEFAULT 0,
+    reference_price DECIMAL     NOT NULL,
+    last_price      DECIMAL     NOT NULL,
+    triggered       BOOLEAN     NOT NULL DEFAULT false,
+    version         BIGINT      NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_alert_rules_favorite FOREIGN KEY (customer_id, product_id)
+        REFERENCES favorites (customer_id, product_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX uniq_alert_rules_customer_product ON alert_rules (customer_id, product_id);
+CREATE INDEX idx_alert_rules_product_id ON alert_rules (product_id);
+
+-- Alertas emitidos, mantidos mesmo que a regra seja removida.
+CREATE TABLE price_alerts (
+    id              BIGSERIAL PRIMARY KEY,
+    customer_id     BIGINT      NOT NULL,
+    product_id      BIGINT      NOT NULL,
+    rule_id         BIGINT      NOT NULL,
+    kind            TEXT        NOT NULL,
+    threshold       DECIMAL     NOT NULL,
+    old_price       DECIMAL     NOT NULL,
+    new_price       DECIMAL     NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    acknowledged_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_price_alerts_customer ON price_alerts (customer_id, created_at DESC);
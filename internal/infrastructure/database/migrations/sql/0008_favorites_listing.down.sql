@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_favorites_customer_title;
+DROP INDEX IF EXISTS idx_favorites_customer_price;
+DROP INDEX IF EXISTS idx_favorites_customer_created;
+
+ALTER TABLE favorites
+    DROP COLUMN IF EXISTS category,
+    DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,12 @@
+-- Campos usados na ordenação e nos filtros da listagem de favoritos. Os
+-- favoritos já existentes ficam com a data desta migração.
+ALTER TABLE favorites
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN category   TEXT        NOT NULL DEFAULT '';
+
+CREATE INDEX idx_favorites_customer_created ON favorites (customer_id, created_at DESC, id DESC);
+CREATE INDEX idx_favorites_customer_price ON favorites (customer_id, price, id);
+CREATE INDEX idx_favorites_customer_title ON favorites (customer_id, title, id);
+
+-- Força a próxima sincronização a preencher a categoria de todos os favoritos.
+UPDATE favorites SET last_synced_at = NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_customers_email_prefix;
+DROP INDEX IF EXISTS idx_customers_name_prefix;
+DROP INDEX IF EXISTS idx_customers_email_id;
+DROP INDEX IF EXISTS idx_customers_name_id;
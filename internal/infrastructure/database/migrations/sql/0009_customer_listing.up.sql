@@ -0,0 +1,9 @@
+-- Ordenação por nome e e-mail na listagem de clientes, paginada por keyset.
+-- As colunas aceitam NULL, então a ordem é sobre COALESCE(coluna, ''), a
+-- mesma expressão usada pela consulta.
+CREATE INDEX idx_customers_name_id ON customers ((COALESCE(name, '')), id);
+CREATE INDEX idx_customers_email_id ON customers ((COALESCE(email, '')), id);
+
+-- Busca por prefixo e por e-mail sem diferenciar maiúsculas.
+CREATE INDEX idx_customers_name_prefix ON customers (LOWER(name) text_pattern_ops);
+CREATE INDEX idx_customers_email_prefix ON customers (LOWER(email) text_pattern_ops);
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type AlertRule struct {
+	ID             uint    `gorm:"primaryKey"`
+	CustomerID     uint    `gorm:"not null;uniqueIndex:uniq_alert_rules_customer_product"`
+	ProductID      uint    `gorm:"not null;index:idx_alert_rules_product_id;uniqueIndex:uniq_alert_rules_customer_product"`
+	Kind           string  `gorm:"not null"`
+	Threshold      float32 `gorm:"not null"`
+	ReferencePrice float32 `gorm:"not null"`
+	LastPrice      float32 `gorm:"not null"`
+	Triggered      bool    `gorm:"not null"`
+	Version        int64   `gorm:"not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type PriceAlert struct {
+	ID             uint      `gorm:"primaryKey"`
+	CustomerID     uint      `gorm:"not null;index:idx_price_alerts_customer,priority:1"`
+	ProductID      uint      `gorm:"not null"`
+	RuleID         uint      `gorm:"not null"`
+	Kind           string    `gorm:"not null"`
+	Threshold      float32   `gorm:"not null"`
+	OldPrice       float32   `gorm:"not null"`
+	NewPrice       float32   `gorm:"not null"`
+	CreatedAt      time.Time `gorm:"index:idx_price_alerts_customer,priority:2,sort:desc"`
+	AcknowledgedAt *time.Time
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type CustomerCredential struct {
+	CustomerID   uint   `gorm:"primaryKey"`
+	PasswordHash string `gorm:"not null"`
+	Roles        string `gorm:"not null;default:customer"` // separados por vírgula
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -1,10 +1,18 @@
 package models
 
+import "time"
+
 type Favorite struct {
 	ID         uint    `gorm:"primaryKey"`
 	CustomerID uint    `gorm:"not null;index;uniqueIndex:uniq_customer_product"`
-	ProductID  uint    `gorm:"not null;uniqueIndex:uniq_customer_product"`
+	ProductID  uint    `gorm:"not null;index:idx_favorites_product_id;uniqueIndex:uniq_customer_product"`
 	Title      string  `gorm:"not null"`
 	ImageUrl   string  `gorm:"not null"`
+	Category   string  `gorm:"not null;default:''"`
 	Price      float32 `gorm:"not null"`
+	// FavoritedPrice é o preço no momento em que o produto foi favoritado.
+	FavoritedPrice float32 `gorm:"not null"`
+	Available      bool    `gorm:"not null;default:true"`
+	LastSyncedAt   *time.Time
+	CreatedAt      time.Time
 }
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type ProductPriceHistory struct {
+	ID         uint      `gorm:"primaryKey"`
+	ProductID  uint      `gorm:"not null;index:idx_product_price_history_product,priority:1"`
+	Price      float32   `gorm:"not null"`
+	RecordedAt time.Time `gorm:"not null;index:idx_product_price_history_product,priority:2,sort:desc"`
+}
+
+func (ProductPriceHistory) TableName() string {
+	return "product_price_history"
+}
@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/alert"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AlertRepository struct {
+	db *gorm.DB
+}
+
+func NewAlertRepository(db *gorm.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+// SaveRule substitui a regra existente do favorito, se houver, voltando o
+// estado de disparo ao inicial.
+func (r *AlertRepository) SaveRule(rule domain.Rule) (domain.Rule, error) {
+	model := models.AlertRule{
+		CustomerID:     rule.CustomerID,
+		ProductID:      rule.ProductID,
+		Kind:           rule.Kind,
+		Threshold:      rule.Threshold,
+		ReferencePrice: rule.ReferencePrice,
+		LastPrice:      rule.LastPrice,
+	}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "customer_id"}, {Name: "product_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"kind":            model.Kind,
+			"threshold":       model.Threshold,
+			"reference_price": model.ReferencePrice,
+			"last_price":      model.LastPrice,
+			"triggered":       false,
+			"version":         gorm.Expr("alert_rules.version + 1"),
+			"updated_at":      time.Now(),
+		}),
+	}).Create(&model).Error
+	if err != nil {
+		return domain.Rule{}, err
+	}
+	return r.FindRule(rule.CustomerID, rule.ProductID)
+}
+
+func (r *AlertRepository) FindRule(customerID, productID uint) (domain.Rule, error) {
+	var model models.AlertRule
+	err := r.db.Where("customer_id = ? AND product_id = ?", customerID, productID).Take(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Rule{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Rule{}, err
+	}
+	return toRule(model), nil
+}
+
+func (r *AlertRepository) DeleteRule(customerID, productID uint) error {
+	res := r.db.Where("customer_id = ? AND product_id = ?", customerID, productID).Delete(&models.AlertRule{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AlertRepository) RulesForProduct(productID uint) ([]domain.Rule, error) {
+	var rows []models.AlertRule
+	if err := r.db.Where("product_id = ?", productID).Order("id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.Rule, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, toRule(m))
+	}
+	return out, nil
+}
+
+func (r *AlertRepository) ApplyEvaluation(current, next domain.Rule, alert *domain.Alert) (*domain.Alert, bool, error) {
+	var stored *domain.Alert
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.AlertRule{}).
+			Where("id = ? AND version = ?", current.ID, current.Version).
+			Updates(map[string]interface{}{
+				"last_price": next.LastPrice,
+				"triggered":  next.Triggered,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": time.Now(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		applied = true
+
+		if alert == nil {
+			return nil
+		}
+		model := models.PriceAlert{
+			CustomerID: alert.CustomerID,
+			ProductID:  alert.ProductID,
+			RuleID:     alert.RuleID,
+			Kind:       alert.Kind,
+			Threshold:  alert.Threshold,
+			OldPrice:   alert.OldPrice,
+			NewPrice:   alert.NewPrice,
+		}
+		if err := tx.Create(&model).Error; err != nil {
+			return err
+		}
+		a := toAlert(model)
+		stored = &a
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, applied, nil
+}
+
+func (r *AlertRepository) ListAlerts(customerID uint, unacknowledgedOnly bool, limit int) ([]domain.Alert, error) {
+	q := r.db.Where("customer_id = ?", customerID)
+	if unacknowledgedOnly {
+		q = q.Where("acknowledged_at IS NULL")
+	}
+	var rows []models.PriceAlert
+	if err := q.Order("created_at DESC, id DESC").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.Alert, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, toAlert(m))
+	}
+	return out, nil
+}
+
+// Acknowledge é idempotente: um alerta já reconhecido mantém a data original.
+func (r *AlertRepository) Acknowledge(customerID, alertID uint, at time.Time) (domain.Alert, error) {
+	err := r.db.Model(&models.PriceAlert{}).
+		Where("id = ? AND customer_id = ? AND acknowledged_at IS NULL", alertID, customerID).
+		Update("acknowledged_at", at).Error
+	if err != nil {
+		return domain.Alert{}, err
+	}
+	var model models.PriceAlert
+	err = r.db.Where("id = ? AND customer_id = ?", alertID, customerID).Take(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Alert{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Alert{}, err
+	}
+	return toAlert(model), nil
+}
+
+func toRule(m models.AlertRule) domain.Rule {
+	return domain.Rule{
+		ID:             m.ID,
+		CustomerID:     m.CustomerID,
+		ProductID:      m.ProductID,
+		Kind:           m.Kind,
+		Threshold:      m.Threshold,
+		ReferencePrice: m.ReferencePrice,
+		LastPrice:      m.LastPrice,
+		Triggered:      m.Triggered,
+		Version:        m.Version,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func toAlert(m models.PriceAlert) domain.Alert {
+	return domain.Alert{
+		ID:             m.ID,
+		CustomerID:     m.CustomerID,
+		ProductID:      m.ProductID,
+		RuleID:         m.RuleID,
+		Kind:           m.Kind,
+		Threshold:      m.Threshold,
+		OldPrice:       m.OldPrice,
+		NewPrice:       m.NewPrice,
+		CreatedAt:      m.CreatedAt,
+		AcknowledgedAt: m.AcknowledgedAt,
+	}
+}
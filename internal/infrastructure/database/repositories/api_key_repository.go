@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/authentication"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/models"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) Create(k domain.APIKey, keyHash string) (domain.APIKey, error) {
+	model := models.APIKey{
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		KeyHash:   keyHash,
+		Scopes:    strings.Join(k.Scopes, ","),
+		ExpiresAt: k.ExpiresAt,
+	}
+
+	if err := r.db.Create(&model).Error; err != nil {
+		return domain.APIKey{}, err
+	}
+	return toAPIKey(model), nil
+}
+
+func (r *APIKeyRepository) FindByHash(keyHash string) (domain.APIKey, error) {
+	var model models.APIKey
+	err := r.db.Where("key_hash = ?", keyHash).Take(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.APIKey{}, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	return toAPIKey(model), nil
+}
+
+func (r *APIKeyRepository) List() ([]domain.APIKey, error) {
+	var rows []models.APIKey
+	if err := r.db.Order("id DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.APIKey, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, toAPIKey(m))
+	}
+	return out, nil
+}
+
+func (r *APIKeyRepository) Revoke(id uint, at time.Time) error {
+	res := r.db.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(id uint, at time.Time) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
+
+func toAPIKey(m models.APIKey) domain.APIKey {
+	return domain.APIKey{
+		ID:         m.ID,
+		Name:       m.Name,
+		Prefix:     m.Prefix,
+		Scopes:     strings.Split(m.Scopes, ","),
+		ExpiresAt:  m.ExpiresAt,
+		LastUsedAt: m.LastUsedAt,
+		RevokedAt:  m.RevokedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/authentication"
+	"github.com/vinihss/aiqfome/internal/domain/customer"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/models"
+	"gorm.io/gorm"
+)
+
+type CredentialRepository struct {
+	db *gorm.DB
+}
+
+func NewCredentialRepository(db *gorm.DB) *CredentialRepository {
+	return &CredentialRepository{db: db}
+}
+
+func (r *CredentialRepository) Register(c customer.Customer, passwordHash string, roles []string) (customer.Customer, error) {
+	model := models.Customer{
+		Name:  c.Name,
+		Email: c.Email,
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.CustomerCredential{
+			CustomerID:   model.ID,
+			PasswordHash: passwordHash,
+			Roles:        strings.Join(roles, ","),
+		}).Error
+	})
+	if err != nil {
+		return customer.Customer{}, err
+	}
+
+	return customer.Customer{
+		ID:    model.ID,
+		Name:  model.Name,
+		Email: model.Email,
+	}, nil
+}
+
+func (r *CredentialRepository) FindByEmail(email string) (domain.Credential, error) {
+	var row struct {
+		CustomerID   uint
+		Email        string
+		PasswordHash string
+		Roles        string
+	}
+
+	err := r.db.Table("customer_credentials AS cc").
+		Select("cc.customer_id, c.email, cc.password_hash, cc.roles").
+		Joins("JOIN customers AS c ON c.id = cc.customer_id").
+		Where("LOWER(c.email) = ?", strings.TrimSpace(strings.ToLower(email))).
+		Take(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Credential{}, domain.ErrCredentialNotFound
+	}
+	if err != nil {
+		return domain.Credential{}, err
+	}
+
+	return domain.Credential{
+		CustomerID:   row.CustomerID,
+		Email:        row.Email,
+		PasswordHash: row.PasswordHash,
+		Roles:        strings.Split(row.Roles, ","),
+	}, nil
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/vinihss/aiqfome/internal/domain/customer"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/models"
+	"gorm.io/gorm"
+)
+
+// customersCursor é a posição do último cliente de uma página, junto com a
+// ordenação e os filtros que a geraram. Reaproveitá-lo com outra busca
+// pularia ou repetiria clientes, por isso a combinação é rejeitada.
+type customersCursor struct {
+	Sort   string `json:"s"`
+	Desc   bool   `json:"d"`
+	Search string `json:"q,omitempty"`
+	Email  string `json:"e,omitempty"`
+	Value  string `json:"v,omitempty"`
+	ID     uint   `json:"id"`
+}
+
+func encodeCustomersCursor(q customer.ListQuery, last models.Customer) string {
+	c := customersCursor{
+		Sort:   q.Sort,
+		Desc:   q.Desc,
+		Search: strings.ToLower(q.Search),
+		Email:  strings.ToLower(q.Email),
+		ID:     last.ID,
+	}
+	switch q.Sort {
+	case customer.SortName:
+		c.Value = last.Name
+	case customer.SortEmail:
+		c.Value = last.Email
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCustomersCursor(q customer.ListQuery) (customersCursor, error) {
+	var c customersCursor
+	data, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil || json.Unmarshal(data, &c) != nil {
+		return c, customer.ErrInvalidCursor
+	}
+	if c.Sort != q.Sort || c.Desc != q.Desc || c.ID == 0 {
+		return c, customer.ErrInvalidCursor
+	}
+	if c.Search != strings.ToLower(q.Search) || c.Email != strings.ToLower(q.Email) {
+		return c, customer.ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// List lê uma página de clientes. Com cursor, a leitura é por keyset sobre
+// (coluna de ordenação, id) e o custo não cresce com a página; sem ele, por
+// offset, o que permite saltar para qualquer página. Um item a mais é lido
+// para saber se existe próxima página.
+//
+// name e email aceitam NULL, e uma comparação de tupla com NULL nunca é
+// verdadeira; por isso a ordenação e o keyset usam COALESCE(coluna, ”), a
+// mesma expressão dos índices da migração 0009.
+func (r *CustomerRepository) List(q customer.ListQuery) (customer.Page, error) {
+	var cursor *customersCursor
+	if q.Cursor != "" {
+		c, err := decodeCustomersCursor(q)
+		if err != nil {
+			return customer.Page{}, err
+		}
+		cursor = &c
+	}
+
+	filtered := r.db.Model(&models.Customer{})
+	if q.Search != "" {
+		prefix := strings.ToLower(escapeLike(q.Search)) + "%"
+		filtered = filtered.Where("(LOWER(name) LIKE ? OR LOWER(email) LIKE ?)", prefix, prefix)
+	}
+	if q.Email != "" {
+		filtered = filtered.Where("LOWER(email) = ?", strings.ToLower(q.Email))
+	}
+	filtered = filtered.Session(&gorm.Session{})
+
+	var page customer.Page
+	if err := filtered.Count(&page.Total).Error; err != nil {
+		return customer.Page{}, err
+	}
+
+	column := "id"
+	if q.Sort == customer.SortName || q.Sort == customer.SortEmail {
+		column = "COALESCE(" + q.Sort + ", '')"
+	}
+	cmp, dir := ">", "ASC"
+	if q.Desc {
+		cmp, dir = "<", "DESC"
+	}
+
+	rows := filtered
+	switch {
+	case cursor != nil && column == "id":
+		rows = rows.Where("id "+cmp+" ?", cursor.ID)
+	case cursor != nil:
+		rows = rows.Where("("+column+", id) "+cmp+" (?, ?)", cursor.Value, cursor.ID)
+	default:
+		rows = rows.Offset((q.Page - 1) * q.Size)
+	}
+	if column != "id" {
+		rows = rows.Order(column + " " + dir)
+	}
+
+	var found []models.Customer
+	if err := rows.Order("id " + dir).Limit(q.Size + 1).Find(&found).Error; err != nil {
+		return customer.Page{}, err
+	}
+	if len(found) > q.Size {
+		found = found[:q.Size]
+		page.NextCursor = encodeCustomersCursor(q, found[len(found)-1])
+	}
+	page.Items = make([]customer.Customer, 0, len(found))
+	for _, m := range found {
+		page.Items = append(page.Items, customer.Customer{ID: m.ID, Name: m.Name, Email: m.Email})
+	}
+	return page, nil
+}
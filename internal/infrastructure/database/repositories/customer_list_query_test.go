@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vinihss/aiqfome/internal/domain/customer"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/models"
+)
+
+func TestCustomersCursorIsBoundToSortAndFilters(t *testing.T) {
+	base := customer.ListQuery{Page: 1, Size: 20, Sort: customer.SortName, Search: "Ana", Email: "ana@example.com"}
+	cursor := encodeCustomersCursor(base, models.Customer{ID: 42, Name: "Ana Souza"})
+
+	tests := []struct {
+		name    string
+		change  func(q *customer.ListQuery)
+		wantErr error
+	}{
+		{"same query", func(*customer.ListQuery) {}, nil},
+		{"filters differ only in case", func(q *customer.ListQuery) { q.Search, q.Email = "ANA", "Ana@Example.com" }, nil},
+		{"another page size", func(q *customer.ListQuery) { q.Size = 50 }, nil},
+		{"another search", func(q *customer.ListQuery) { q.Search = "Bruno" }, customer.ErrInvalidCursor},
+		{"search dropped", func(q *customer.ListQuery) { q.Search = "" }, customer.ErrInvalidCursor},
+		{"another email", func(q *customer.ListQuery) { q.Email = "bruno@example.com" }, customer.ErrInvalidCursor},
+		{"email dropped", func(q *customer.ListQuery) { q.Email = "" }, customer.ErrInvalidCursor},
+		{"another sort", func(q *customer.ListQuery) { q.Sort = customer.SortEmail }, customer.ErrInvalidCursor},
+		{"another direction", func(q *customer.ListQuery) { q.Desc = true }, customer.ErrInvalidCursor},
+		{"not a cursor", func(q *customer.ListQuery) { q.Cursor = "%%%" }, customer.ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := base
+			q.Cursor = cursor
+			tt.change(&q)
+
+			c, err := decodeCustomersCursor(q)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("decodeCustomersCursor error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (c.ID != 42 || c.Value != "Ana Souza") {
+				t.Errorf("cursor = %+v, want the position of the last customer", c)
+			}
+		})
+	}
+}
+
+func TestCustomersCursorOfNullNameMatchesCoalesce(t *testing.T) {
+	// Um nome NULL chega do banco como string vazia, o mesmo valor que
+	// COALESCE(name, '') usa no keyset.
+	q := customer.ListQuery{Page: 1, Size: 20, Sort: customer.SortName}
+	q.Cursor = encodeCustomersCursor(q, models.Customer{ID: 7})
+
+	c, err := decodeCustomersCursor(q)
+	if err != nil {
+		t.Fatalf("decodeCustomersCursor: %v", err)
+	}
+	if c.Value != "" || c.ID != 7 {
+		t.Errorf("cursor = %+v, want an empty value and id 7", c)
+	}
+}
@@ -80,22 +80,3 @@ func (r *CustomerRepository) Update(new customer.Customer) (customer.Customer, e
 		Email: model.Email,
 	}, nil
 }
-
-func (r *CustomerRepository) FindAll(offset, size int) ([]customer.Customer, error) {
-	var models []models.Customer
-
-	if err := r.db.Offset(offset).Limit(size).Find(&models).Error; err != nil {
-		return nil, err
-	}
-
-	var customers []customer.Customer
-	for _, model := range models {
-		customers = append(customers, customer.Customer{
-			ID:    model.ID,
-			Name:  model.Name,
-			Email: model.Email,
-		})
-	}
-
-	return customers, nil
-}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/models"
+	"gorm.io/gorm"
+)
+
+// favoritesCursor é a posição do último item de uma página. Ele guarda a
+// ordenação e um hash dos filtros que o geraram para que não seja
+// reaproveitado com outra combinação, o que pularia ou repetiria itens.
+type favoritesCursor struct {
+	Sort      string    `json:"s"`
+	Desc      bool      `json:"d"`
+	Filters   string    `json:"f"`
+	CreatedAt time.Time `json:"c,omitempty"`
+	Price     float32   `json:"p,omitempty"`
+	Title     string    `json:"t,omitempty"`
+	ID        uint      `json:"id"`
+}
+
+func encodeCursor(q domain.ListQuery, last models.Favorite) string {
+	c := favoritesCursor{Sort: q.Sort, Desc: q.Desc, Filters: filtersHash(q), ID: last.ID}
+	switch q.Sort {
+	case domain.SortPrice:
+		c.Price = last.Price
+	case domain.SortTitle:
+		c.Title = last.Title
+	default:
+		c.CreatedAt = last.CreatedAt
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(q domain.ListQuery) (favoritesCursor, error) {
+	var c favoritesCursor
+	data, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil || json.Unmarshal(data, &c) != nil {
+		return c, domain.ErrInvalidCursor
+	}
+	if c.Sort != q.Sort || c.Desc != q.Desc || c.Filters != filtersHash(q) || c.ID == 0 {
+		return c, domain.ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// filtersHash resume os filtros da consulta. Busca e categoria não
+// diferenciam maiúsculas, então são normalizadas antes.
+func filtersHash(q domain.ListQuery) string {
+	data, _ := json.Marshal(struct {
+		MinPrice *float32 `json:"min,omitempty"`
+		MaxPrice *float32 `json:"max,omitempty"`
+		Search   string   `json:"q,omitempty"`
+		Category string   `json:"cat,omitempty"`
+	}{q.MinPrice, q.MaxPrice, strings.ToLower(q.Search), strings.ToLower(q.Category)})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// listFromDB pagina por keyset: a página seguinte começa depois da tupla
+// (coluna de ordenação, id) do último item, usando os índices criados na
+// migração 0008. Um item a mais é lido para saber se existe próxima página.
+func (r *FavoriteRepository) listFromDB(q domain.ListQuery) (domain.Page, error) {
+	var cursor *favoritesCursor
+	if q.Cursor != "" {
+		c, err := decodeCursor(q)
+		if err != nil {
+			return domain.Page{}, err
+		}
+		cursor = &c
+	}
+
+	filtered := r.db.Model(&models.Favorite{}).Where("customer_id = ?", q.CustomerID)
+	if q.MinPrice != nil {
+		filtered = filtered.Where("price >= CAST(CAST(? AS REAL) AS NUMERIC)", *q.MinPrice)
+	}
+	if q.MaxPrice != nil {
+		filtered = filtered.Where("price <= CAST(CAST(? AS REAL) AS NUMERIC)", *q.MaxPrice)
+	}
+	if q.Search != "" {
+		filtered = filtered.Where("title ILIKE ?", "%"+escapeLike(q.Search)+"%")
+	}
+	if q.Category != "" {
+		filtered = filtered.Where("LOWER(category) = LOWER(?)", q.Category)
+	}
+	filtered = filtered.Session(&gorm.Session{})
+
+	var page domain.Page
+	if err := filtered.Count(&page.Total).Error; err != nil {
+		return domain.Page{}, err
+	}
+
+	column, placeholder := "created_at", "?"
+	switch q.Sort {
+	case domain.SortPrice:
+		column, placeholder = "price", "CAST(CAST(? AS REAL) AS NUMERIC)"
+	case domain.SortTitle:
+		column = "title"
+	}
+	cmp, dir := ">", "ASC"
+	if q.Desc {
+		cmp, dir = "<", "DESC"
+	}
+
+	rows := filtered
+	if c := cursor; c != nil {
+		var value interface{} = c.CreatedAt
+		switch q.Sort {
+		case domain.SortPrice:
+			value = c.Price
+		case domain.SortTitle:
+			value = c.Title
+		}
+		rows = rows.Where("("+column+", id) "+cmp+" ("+placeholder+", ?)", value, c.ID)
+	}
+
+	var found []models.Favorite
+	err := rows.Order(column + " " + dir).Order("id " + dir).Limit(q.Limit + 1).Find(&found).Error
+	if err != nil {
+		return domain.Page{}, err
+	}
+	if len(found) > q.Limit {
+		found = found[:q.Limit]
+		page.NextCursor = encodeCursor(q, found[len(found)-1])
+	}
+	page.Items = make([]domain.Favorite, 0, len(found))
+	for _, m := range found {
+		page.Items = append(page.Items, toFavorite(m))
+	}
+	return page, nil
+}
+
+// escapeLike impede que % e _ digitados pelo cliente virem curingas.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,254 @@
+package repositories
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/migrations"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestFavoritesCursorIsBoundToSortAndFilters(t *testing.T) {
+	minPrice, maxPrice := float32(10), float32(99.9)
+	base := domain.ListQuery{
+		CustomerID: 1,
+		Limit:      20,
+		Sort:       domain.SortPrice,
+		MinPrice:   &minPrice,
+		MaxPrice:   &maxPrice,
+		Search:     "Mochila",
+		Category:   "Bags",
+	}
+	cursor := encodeCursor(base, models.Favorite{ID: 42, Price: 59.9})
+
+	tests := []struct {
+		name    string
+		change  func(q *domain.ListQuery)
+		wantErr error
+	}{
+		{"same query", func(*domain.ListQuery) {}, nil},
+		{"filters differ only in case", func(q *domain.ListQuery) { q.Search, q.Category = "MOCHILA", "bags" }, nil},
+		{"same prices in new pointers", func(q *domain.ListQuery) {
+			lo, hi := float32(10), float32(99.9)
+			q.MinPrice, q.MaxPrice = &lo, &hi
+		}, nil},
+		{"another limit", func(q *domain.ListQuery) { q.Limit = 50 }, nil},
+		{"another sort", func(q *domain.ListQuery) { q.Sort = domain.SortTitle }, domain.ErrInvalidCursor},
+		{"another direction", func(q *domain.ListQuery) { q.Desc = true }, domain.ErrInvalidCursor},
+		{"another min_price", func(q *domain.ListQuery) { p := float32(20); q.MinPrice = &p }, domain.ErrInvalidCursor},
+		{"max_price dropped", func(q *domain.ListQuery) { q.MaxPrice = nil }, domain.ErrInvalidCursor},
+		{"another search", func(q *domain.ListQuery) { q.Search = "Bolsa" }, domain.ErrInvalidCursor},
+		{"category dropped", func(q *domain.ListQuery) { q.Category = "" }, domain.ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := base
+			q.Cursor = cursor
+			tt.change(&q)
+
+			c, err := decodeCursor(q)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("decodeCursor error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (c.ID != 42 || c.Price != 59.9) {
+				t.Errorf("cursor = %+v, want the position of the last favorite", c)
+			}
+		})
+	}
+}
+
+func TestFavoritesCursorRejectsGarbage(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "%%%"},
+		{"not json", encode("not json")},
+		{"json without id", encode(`{"s":"created_at","d":false,"f":"` + filtersHash(domain.ListQuery{}) + `"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := domain.ListQuery{CustomerID: 1, Limit: 20, Sort: domain.SortCreatedAt, Cursor: tt.cursor}
+			if _, err := decodeCursor(q); !errors.Is(err, domain.ErrInvalidCursor) {
+				t.Errorf("decodeCursor error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"50%_off":  `50\%\_off`,
+		`c:\temp`:  `c:\\temp`,
+		"mochila":  "mochila",
+		`100\%`:    `100\\\%`,
+		"__init__": `\_\_init\_\_`,
+	}
+	for in, want := range tests {
+		if got := escapeLike(in); got != want {
+			t.Errorf("escapeLike(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// openTestDB conecta ao Postgres de TEST_DATABASE_URL (no formato
+// postgres://) em um schema próprio, com todas as migrações aplicadas. Sem a
+// variável o teste é pulado.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	silent := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+
+	admin, err := gorm.Open(postgres.Open(dsn), silent)
+	if err != nil {
+		t.Fatalf("connecting to %s: %v", dsn, err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("creating schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("TEST_DATABASE_URL must be a postgres:// URL: %v", err)
+	}
+	params := u.Query()
+	params.Set("search_path", schema)
+	u.RawQuery = params.Encode()
+
+	db, err := gorm.Open(postgres.Open(u.String()), silent)
+	if err != nil {
+		t.Fatalf("connecting to schema %s: %v", schema, err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.NewMigrator(sqlDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("applying migrations: %v", err)
+	}
+	return db
+}
+
+func TestListFromDBPagesAcrossTiesWithoutGapsOrRepeats(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewFavoriteRepository(db, nil)
+
+	// Preços, títulos e datas se repetem para que o desempate pelo id decida
+	// onde cada página começa. 9.99 não tem representação exata em float32.
+	createdAt := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	seed := []models.Favorite{
+		{Title: "Mochila", Price: 9.99, CreatedAt: createdAt},
+		{Title: "Mochila", Price: 9.99, CreatedAt: createdAt},
+		{Title: "Bolsa", Price: 9.99, CreatedAt: createdAt.Add(time.Hour)},
+		{Title: "Mochila", Price: 20, CreatedAt: createdAt},
+		{Title: "Bolsa", Price: 20, CreatedAt: createdAt.Add(time.Hour)},
+		{Title: "Carteira", Price: 5, CreatedAt: createdAt.Add(-time.Hour)},
+		{Title: "Bolsa", Price: 9.99, CreatedAt: createdAt},
+	}
+	for i := range seed {
+		seed[i].CustomerID = 1
+		seed[i].ProductID = uint(i + 1)
+		seed[i].ImageUrl = "https://example.com/image.png"
+		seed[i].FavoritedPrice = seed[i].Price
+		if err := db.Create(&seed[i]).Error; err != nil {
+			t.Fatalf("seeding favorites: %v", err)
+		}
+	}
+	// Um favorito de outro cliente não pode aparecer na listagem.
+	other := models.Favorite{CustomerID: 2, ProductID: 1, Title: "Mochila", ImageUrl: "x", Price: 9.99, CreatedAt: createdAt}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// orders ordenam como o banco: pela coluna e, no empate, pelo id.
+	orders := map[string]func(a, b models.Favorite) int{
+		domain.SortCreatedAt: func(a, b models.Favorite) int { return a.CreatedAt.Compare(b.CreatedAt) },
+		domain.SortPrice: func(a, b models.Favorite) int {
+			switch {
+			case a.Price < b.Price:
+				return -1
+			case a.Price > b.Price:
+				return 1
+			}
+			return 0
+		},
+		domain.SortTitle: func(a, b models.Favorite) int { return strings.Compare(a.Title, b.Title) },
+	}
+
+	for sortBy, compare := range orders {
+		for _, desc := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s desc=%v", sortBy, desc), func(t *testing.T) {
+				expected := slices.Clone(seed)
+				sort.SliceStable(expected, func(i, j int) bool {
+					c := compare(expected[i], expected[j])
+					if c == 0 {
+						c = int(expected[i].ID) - int(expected[j].ID)
+					}
+					if desc {
+						return c > 0
+					}
+					return c < 0
+				})
+				var want []uint
+				for _, f := range expected {
+					want = append(want, f.ID)
+				}
+
+				q := domain.ListQuery{CustomerID: 1, Limit: 2, Sort: sortBy, Desc: desc}
+				var got []uint
+				for pages := 0; ; pages++ {
+					if pages > len(seed) {
+						t.Fatalf("pagination did not end, ids so far %v", got)
+					}
+					page, err := repo.listFromDB(q)
+					if err != nil {
+						t.Fatalf("listFromDB: %v", err)
+					}
+					if page.Total != int64(len(seed)) {
+						t.Errorf("Total = %d, want %d", page.Total, len(seed))
+					}
+					for _, item := range page.Items {
+						got = append(got, item.ID)
+					}
+					if page.NextCursor == "" {
+						break
+					}
+					q.Cursor = page.NextCursor
+				}
+				if !slices.Equal(got, want) {
+					t.Errorf("ids = %v, want %v", got, want)
+				}
+			})
+		}
+	}
+}
@@ -1,10 +1,20 @@
 package repositories
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
-	"github.com/redis/go-redis/v9"
+	"fmt"
+	"log"
+	"slices"
+	"sync/atomic"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+
 	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
 	"github.com/vinihss/aiqfome/internal/infrastructure/database/models"
 	"gorm.io/gorm"
@@ -13,43 +23,69 @@ import (
 type FavoriteRepository struct {
 	db    *gorm.DB
 	cache *redis.Client
+
+	// loads agrupa leituras concorrentes da mesma página em uma única
+	// consulta ao Postgres quando ela não está no cache.
+	loads singleflight.Group
+	stats cacheCounters
+	// load lê uma página do Postgres; os testes a substituem para exercitar
+	// o cache sem um banco.
+	load func(q domain.ListQuery) (domain.Page, error)
 }
 
 const (
-	// Cache TTL para lista de favoritos por cliente
+	// Cache TTL para as páginas de favoritos de um cliente
 	favoritesListTTL = 15 * time.Minute
-	// Chave de cache para lista de favoritos
+	// Hash com as páginas de favoritos de um cliente, uma por consulta
 	favoritesListKey = "favorites:customer:%d"
+	// Versão da lista, incrementada a cada escrita. Uma leitura só grava no
+	// cache se a versão não mudou desde que começou.
+	favoritesVersionKey = favoritesListKey + ":version"
+
+	// cacheTimeout limita cada operação no Redis; acima disso a leitura vai
+	// direto ao banco.
+	cacheTimeout = 200 * time.Millisecond
 )
 
+// CacheStats são os contadores acumulados do cache de favoritos.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Errors uint64
+}
+
+type cacheCounters struct {
+	hits, misses, errors atomic.Uint64
+}
+
 func NewFavoriteRepository(db *gorm.DB, cache *redis.Client) *FavoriteRepository {
-	return &FavoriteRepository{
+	r := &FavoriteRepository{
 		db:    db,
 		cache: cache,
 	}
+	r.load = r.listFromDB
+	return r
 }
 
 func (r *FavoriteRepository) Create(f domain.Favorite) (domain.Favorite, error) {
 	model := models.Favorite{
-		CustomerID: f.CustomerID,
-		ProductID:  f.ProductID,
-		Title:      f.Title,
-		ImageUrl:   f.ImageUrl,
-		Price:      f.Price,
+		CustomerID:     f.CustomerID,
+		ProductID:      f.ProductID,
+		Title:          f.Title,
+		ImageUrl:       f.ImageUrl,
+		Category:       f.Category,
+		Price:          f.Price,
+		FavoritedPrice: f.Price,
+		Available:      true,
+		LastSyncedAt:   f.LastSyncedAt,
 	}
 
 	if err := r.db.Create(&model).Error; err != nil {
 		return domain.Favorite{}, err
 	}
+	r.invalidate(model.CustomerID)
 
-	return domain.Favorite{
-		ID:         model.ID,
-		CustomerID: model.CustomerID,
-		ProductID:  model.ProductID,
-		Title:      model.Title,
-		ImageUrl:   model.ImageUrl,
-		Price:      model.Price,
-	}, nil
+	return toFavorite(model), nil
 }
 
 func (r *FavoriteRepository) Exists(customerID uint, productID uint) (bool, error) {
@@ -62,23 +98,47 @@ func (r *FavoriteRepository) Exists(customerID uint, productID uint) (bool, erro
 	return count > 0, nil
 }
 
-func (r *FavoriteRepository) ListByCustomer(customerID uint) ([]domain.Favorite, error) {
-	var rows []models.Favorite
-	if err := r.db.Where("customer_id = ?", customerID).Order("id DESC").Find(&rows).Error; err != nil {
-		return nil, err
-	}
-	out := make([]domain.Favorite, 0, len(rows))
-	for _, m := range rows {
-		out = append(out, domain.Favorite{
-			ID:         m.ID,
-			CustomerID: m.CustomerID,
-			ProductID:  m.ProductID,
-			Title:      m.Title,
-			ImageUrl:   m.ImageUrl,
-			Price:      m.Price,
-		})
+func (r *FavoriteRepository) Find(customerID uint, productID uint) (domain.Favorite, error) {
+	var m models.Favorite
+	err := r.db.Where("customer_id = ? AND product_id = ?", customerID, productID).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Favorite{}, domain.ErrNotFound
 	}
-	return out, nil
+	if err != nil {
+		return domain.Favorite{}, err
+	}
+	return toFavorite(m), nil
+}
+
+// ListByCustomer usa cache-aside: tenta o Redis e, em caso de miss ou erro,
+// lê do Postgres e repopula o cache. Falhas do Redis nunca são devolvidas ao
+// chamador.
+func (r *FavoriteRepository) ListByCustomer(q domain.ListQuery) (domain.Page, error) {
+	if r.cache == nil {
+		return r.load(q)
+	}
+
+	key := fmt.Sprintf(favoritesListKey, q.CustomerID)
+	field := pageField(q)
+	cached, hit, cacheErr := r.cachedPage(key, field)
+	if hit {
+		return cached, nil
+	}
+
+	v, err, _ := r.loads.Do(key+"|"+field, func() (interface{}, error) {
+		if cacheErr != nil {
+			// Com o Redis fora, nem tenta repopular o cache.
+			return r.load(q)
+		}
+		return r.loadAndCache(q, key, field)
+	})
+	if err != nil {
+		return domain.Page{}, err
+	}
+	// A página é compartilhada entre as chamadas agrupadas.
+	page := v.(domain.Page)
+	page.Items = slices.Clone(page.Items)
+	return page, nil
 }
 
 func (r *FavoriteRepository) Delete(customerID uint, productID uint) error {
@@ -88,11 +148,147 @@ func (r *FavoriteRepository) Delete(customerID uint, productID uint) error {
 	}
 
 	if res.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+		return domain.ErrNotFound
 	}
+	r.invalidate(customerID)
 	return nil
 }
 
+// CacheStats devolve os contadores de hit, miss e erro do cache de listas.
+func (r *FavoriteRepository) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   r.stats.hits.Load(),
+		Misses: r.stats.misses.Load(),
+		Errors: r.stats.errors.Load(),
+	}
+}
+
+func toFavorite(m models.Favorite) domain.Favorite {
+	return domain.Favorite{
+		ID:             m.ID,
+		CustomerID:     m.CustomerID,
+		ProductID:      m.ProductID,
+		Title:          m.Title,
+		ImageUrl:       m.ImageUrl,
+		Category:       m.Category,
+		Price:          m.Price,
+		FavoritedPrice: m.FavoritedPrice,
+		Available:      m.Available,
+		LastSyncedAt:   m.LastSyncedAt,
+		CreatedAt:      m.CreatedAt,
+	}
+}
+
+// pageField identifica a consulta dentro do hash do cliente.
+func pageField(q domain.ListQuery) string {
+	data, _ := json.Marshal(q)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// cachedPage devolve a página em cache. hit é falso em caso de miss; err só
+// é preenchido quando o Redis falhou.
+func (r *FavoriteRepository) cachedPage(key, field string) (page domain.Page, hit bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cacheTimeout)
+	defer cancel()
+
+	data, err := r.cache.HGet(ctx, key, field).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			r.stats.misses.Add(1)
+			return domain.Page{}, false, nil
+		}
+		r.stats.errors.Add(1)
+		log.Printf("favorites cache: reading %s: %v", key, err)
+		return domain.Page{}, false, err
+	}
+
+	if err := json.Unmarshal(data, &page); err != nil {
+		// Entrada corrompida: tratada como miss e sobrescrita na recarga.
+		r.stats.errors.Add(1)
+		log.Printf("favorites cache: decoding %s: %v", key, err)
+		return domain.Page{}, false, nil
+	}
+	r.stats.hits.Add(1)
+	return page, true, nil
+}
+
+// loadAndCache lê a página do banco e a grava no cache, a menos que uma
+// escrita concorrente tenha incrementado a versão durante a leitura, caso em
+// que o resultado já pode estar desatualizado e não é gravado. A consulta ao
+// Postgres acontece fora do WATCH, para não prender uma conexão do Redis
+// enquanto o banco responde: a versão é lida antes e conferida de novo
+// dentro da transação.
+func (r *FavoriteRepository) loadAndCache(q domain.ListQuery, key, field string) (domain.Page, error) {
+	versionKey := fmt.Sprintf(favoritesVersionKey, q.CustomerID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cacheTimeout)
+	before, err := r.cache.Get(ctx, versionKey).Result()
+	cancel()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		// Redis indisponível: lê do banco sem repopular o cache.
+		r.stats.errors.Add(1)
+		log.Printf("favorites cache: reading %s: %v", versionKey, err)
+		return r.load(q)
+	}
+
+	out, err := r.load(q)
+	if err != nil {
+		return domain.Page{}, err
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return domain.Page{}, err
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), cacheTimeout)
+	defer cancel()
+	err = r.cache.Watch(ctx, func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, versionKey).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if current != before {
+			return redis.TxFailedErr
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, field, data)
+			pipe.Expire(ctx, key, favoritesListTTL)
+			return nil
+		})
+		return err
+	}, versionKey)
+	if err != nil && !errors.Is(err, redis.TxFailedErr) {
+		r.stats.errors.Add(1)
+		log.Printf("favorites cache: writing %s: %v", key, err)
+	}
+	return out, nil
+}
+
+// invalidate remove todas as páginas do cliente do cache e incrementa a
+// versão, impedindo que leituras em andamento gravem um resultado antigo. Se o Redis falhar, a lista fica
+// desatualizada até o TTL expirar.
+func (r *FavoriteRepository) invalidate(customerID uint) {
+	if r.cache == nil {
+		return
+	}
+	key := fmt.Sprintf(favoritesListKey, customerID)
+	versionKey := fmt.Sprintf(favoritesVersionKey, customerID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cacheTimeout)
+	defer cancel()
+	_, err := r.cache.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, versionKey)
+		pipe.Expire(ctx, versionKey, 2*favoritesListTTL)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		r.stats.errors.Add(1)
+		log.Printf("favorites cache: invalidating %s: %v", key, err)
+	}
+}
+
 // Helper para traduzir erros de unicidade, caso queira tratar em camadas superiores
 func isUniqueConstraintErr(err error) bool {
 	if err == nil {
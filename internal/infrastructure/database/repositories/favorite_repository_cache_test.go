@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
+	"github.com/vinihss/aiqfome/internal/infrastructure/redistest"
+)
+
+// countingLoader substitui a leitura do Postgres, devolvendo uma página com
+// um favorito por cliente e contando as chamadas.
+type countingLoader struct {
+	calls atomic.Int32
+	// before, se informado, roda no início de cada leitura.
+	before func(q domain.ListQuery)
+}
+
+func (l *countingLoader) load(q domain.ListQuery) (domain.Page, error) {
+	l.calls.Add(1)
+	if l.before != nil {
+		l.before(q)
+	}
+	return domain.Page{
+		Items: []domain.Favorite{{ID: q.CustomerID * 10, CustomerID: q.CustomerID, Title: fmt.Sprintf("sort %s", q.Sort)}},
+		Total: 1,
+	}, nil
+}
+
+func newCachedRepository(t *testing.T) (*FavoriteRepository, *countingLoader, *redistest.Server) {
+	t.Helper()
+	srv := redistest.NewServer(t)
+	repo := NewFavoriteRepository(nil, srv.Client(t))
+	loader := &countingLoader{}
+	repo.load = loader.load
+	return repo, loader, srv
+}
+
+func listQuery(customerID uint, sort string) domain.ListQuery {
+	return domain.ListQuery{CustomerID: customerID, Limit: domain.DefaultListLimit, Sort: sort, Desc: true}
+}
+
+func mustList(t *testing.T, repo *FavoriteRepository, q domain.ListQuery) domain.Page {
+	t.Helper()
+	page, err := repo.ListByCustomer(q)
+	if err != nil {
+		t.Fatalf("ListByCustomer: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].CustomerID != q.CustomerID {
+		t.Fatalf("ListByCustomer(%d) = %+v", q.CustomerID, page)
+	}
+	return page
+}
+
+func TestListByCustomerCachesEachQuery(t *testing.T) {
+	repo, loader, _ := newCachedRepository(t)
+	byDate := listQuery(1, domain.SortCreatedAt)
+	byPrice := listQuery(1, domain.SortPrice)
+
+	mustList(t, repo, byDate)
+	cached := mustList(t, repo, byDate)
+	if cached.Items[0].Title != "sort created_at" {
+		t.Errorf("cached page = %+v", cached)
+	}
+	if got := loader.calls.Load(); got != 1 {
+		t.Errorf("database reads = %d, want 1", got)
+	}
+
+	// Outra ordenação é outra entrada do hash.
+	mustList(t, repo, byPrice)
+	mustList(t, repo, byPrice)
+	if got := loader.calls.Load(); got != 2 {
+		t.Errorf("database reads = %d, want 2", got)
+	}
+
+	stats := repo.CacheStats()
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Errors != 0 {
+		t.Errorf("stats = %+v, want 2 hits, 2 misses and no errors", stats)
+	}
+}
+
+func TestListByCustomerKeepsTheOriginalKey(t *testing.T) {
+	repo, _, srv := newCachedRepository(t)
+	q := listQuery(3, domain.SortTitle)
+	mustList(t, repo, q)
+
+	cached, err := srv.Client(t).HGet(context.Background(), "favorites:customer:3", pageField(q)).Result()
+	if err != nil || cached == "" {
+		t.Errorf("page under favorites:customer:3 = %q, %v; want the cached page", cached, err)
+	}
+}
+
+func TestInvalidateDropsEveryPageOfTheCustomer(t *testing.T) {
+	repo, loader, _ := newCachedRepository(t)
+	queries := []domain.ListQuery{listQuery(1, domain.SortCreatedAt), listQuery(1, domain.SortTitle), listQuery(2, domain.SortCreatedAt)}
+	for _, q := range queries {
+		mustList(t, repo, q)
+	}
+
+	repo.invalidate(1)
+	for _, q := range queries {
+		mustList(t, repo, q)
+	}
+
+	// As duas páginas do cliente 1 são relidas; a do cliente 2 continua em
+	// cache.
+	if got := loader.calls.Load(); got != 5 {
+		t.Errorf("database reads = %d, want 5", got)
+	}
+}
+
+func TestConcurrentMissesShareOneDatabaseRead(t *testing.T) {
+	repo, loader, _ := newCachedRepository(t)
+	release := make(chan struct{})
+	loader.before = func(domain.ListQuery) { <-release }
+
+	const callers = 20
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.ListByCustomer(listQuery(3, domain.SortCreatedAt)); err != nil {
+				t.Errorf("ListByCustomer: %v", err)
+			}
+		}()
+	}
+	// Espera todos os chamadores passarem pelo cache antes de liberar a
+	// leitura compartilhada.
+	for repo.CacheStats().Misses < callers {
+		runtime.Gosched()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := loader.calls.Load(); got != 1 {
+		t.Errorf("database reads = %d, want 1", got)
+	}
+}
+
+func TestWriteDuringReadIsNotCached(t *testing.T) {
+	repo, loader, _ := newCachedRepository(t)
+	// Uma escrita confirma enquanto a primeira leitura está no banco; o
+	// resultado dessa leitura não pode ir para o cache.
+	var once sync.Once
+	loader.before = func(q domain.ListQuery) {
+		once.Do(func() { repo.invalidate(q.CustomerID) })
+	}
+
+	q := listQuery(4, domain.SortCreatedAt)
+	mustList(t, repo, q)
+	mustList(t, repo, q)
+	mustList(t, repo, q)
+
+	if got := loader.calls.Load(); got != 2 {
+		t.Errorf("database reads = %d, want 2: the first read must not be cached", got)
+	}
+}
+
+func TestRedisOutageFallsBackToDatabase(t *testing.T) {
+	repo, loader, srv := newCachedRepository(t)
+	q := listQuery(5, domain.SortCreatedAt)
+	mustList(t, repo, q)
+
+	srv.Close()
+	mustList(t, repo, q)
+	mustList(t, repo, q)
+	repo.invalidate(5) // não deve falhar nem travar
+
+	if got := loader.calls.Load(); got != 3 {
+		t.Errorf("database reads = %d, want 3", got)
+	}
+	if stats := repo.CacheStats(); stats.Errors < 2 {
+		t.Errorf("stats = %+v, want the Redis errors counted", stats)
+	}
+}
+
+func TestCorruptEntryIsReloaded(t *testing.T) {
+	repo, loader, srv := newCachedRepository(t)
+	q := listQuery(6, domain.SortCreatedAt)
+	key := fmt.Sprintf(favoritesListKey, q.CustomerID)
+	if err := srv.Client(t).HSet(context.Background(), key, pageField(q), "not json").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	mustList(t, repo, q)
+	mustList(t, repo, q)
+
+	if got := loader.calls.Load(); got != 1 {
+		t.Errorf("database reads = %d, want 1", got)
+	}
+	if stats := repo.CacheStats(); stats.Errors != 1 || stats.Hits != 1 {
+		t.Errorf("stats = %+v, want 1 error and 1 hit", stats)
+	}
+}
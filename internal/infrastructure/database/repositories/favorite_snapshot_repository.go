@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/models"
+	"gorm.io/gorm"
+)
+
+// favoritesSyncLockKey identifica o advisory lock que impede duas instâncias
+// de sincronizar os favoritos ao mesmo tempo.
+const favoritesSyncLockKey int64 = 7_346_221_002
+
+func (r *FavoriteRepository) StaleProductIDs(afterID uint, olderThan time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Favorite{}).
+		Distinct("product_id").
+		Where("product_id > ? AND (last_synced_at IS NULL OR last_synced_at < ?)", afterID, olderThan).
+		Order("product_id").
+		Limit(limit).
+		Pluck("product_id", &ids).Error
+	return ids, err
+}
+
+// UpdateSnapshot invalida o cache apenas dos clientes cujo favorito mudou de
+// fato; para os demais só last_synced_at é atualizado. O preço é comparado
+// como em PriceHistoryRepository.Record.
+func (r *FavoriteRepository) UpdateSnapshot(s domain.Snapshot, syncedAt time.Time) error {
+	var changed []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Favorite{}).
+			Where("product_id = ? AND (title <> ? OR image_url <> ? OR category <> ? OR price <> CAST(CAST(? AS REAL) AS NUMERIC) OR NOT available)",
+				s.ProductID, s.Title, s.ImageUrl, s.Category, s.Price).
+			Pluck("customer_id", &changed).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.Favorite{}).
+			Where("product_id = ?", s.ProductID).
+			Updates(map[string]interface{}{
+				"title":          s.Title,
+				"image_url":      s.ImageUrl,
+				"category":       s.Category,
+				"price":          s.Price,
+				"available":      true,
+				"last_synced_at": syncedAt,
+			}).Error
+	})
+	if err != nil {
+		return err
+	}
+	for _, customerID := range changed {
+		r.invalidate(customerID)
+	}
+	return nil
+}
+
+func (r *FavoriteRepository) MarkUnavailable(productID uint, syncedAt time.Time) error {
+	var changed []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Favorite{}).
+			Where("product_id = ? AND available", productID).
+			Pluck("customer_id", &changed).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.Favorite{}).
+			Where("product_id = ?", productID).
+			Updates(map[string]interface{}{
+				"available":      false,
+				"last_synced_at": syncedAt,
+			}).Error
+	})
+	if err != nil {
+		return err
+	}
+	for _, customerID := range changed {
+		r.invalidate(customerID)
+	}
+	return nil
+}
+
+// WithSyncLock segura um advisory lock do Postgres em uma conexão dedicada
+// enquanto fn executa.
+func (r *FavoriteRepository) WithSyncLock(ctx context.Context, fn func(ctx context.Context) error) (acquired bool, err error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", favoritesSyncLockKey).Scan(&acquired); err != nil {
+		return false, fmt.Errorf("acquiring favorites sync lock: %w", err)
+	}
+	if !acquired {
+		return false, nil
+	}
+	defer func() {
+		_, unlockErr := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", favoritesSyncLockKey)
+		if unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("releasing favorites sync lock: %w", unlockErr))
+		}
+	}()
+
+	return true, fn(ctx)
+}
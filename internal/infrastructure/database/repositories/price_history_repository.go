@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/product"
+	"github.com/vinihss/aiqfome/internal/infrastructure/database/models"
+	"gorm.io/gorm"
+)
+
+type PriceHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceHistoryRepository(db *gorm.DB) *PriceHistoryRepository {
+	return &PriceHistoryRepository{db: db}
+}
+
+// Record compara com o último preço dentro do próprio INSERT. O preço chega
+// como float4 e é convertido para numeric antes da comparação, do mesmo
+// jeito que é convertido ao ser gravado.
+func (r *PriceHistoryRepository) Record(productID uint, price float32, at time.Time) error {
+	return r.db.Exec(`INSERT INTO product_price_history (product_id, price, recorded_at)
+		SELECT ?, ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1 FROM (
+				SELECT price FROM product_price_history
+				WHERE product_id = ?
+				ORDER BY recorded_at DESC
+				LIMIT 1
+			) last
+			WHERE last.price = CAST(CAST(? AS REAL) AS NUMERIC)
+		)`, productID, price, at, productID, price).Error
+}
+
+func (r *PriceHistoryRepository) History(productID uint, limit int) ([]domain.PricePoint, error) {
+	var rows []models.ProductPriceHistory
+	err := r.db.Where("product_id = ?", productID).
+		Order("recorded_at DESC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.PricePoint, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, domain.PricePoint{ProductID: m.ProductID, Price: m.Price, RecordedAt: m.RecordedAt})
+	}
+	return out, nil
+}
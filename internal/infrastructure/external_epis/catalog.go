@@ -0,0 +1,148 @@
+package external_epis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/vinihss/aiqfome/internal/domain/product"
+)
+
+const (
+	// batchConcurrency limita as chamadas simultâneas feitas por GetProducts.
+	batchConcurrency = 8
+	// listThreshold é a partir de quantos produtos fora do cache GetProducts
+	// busca o catálogo inteiro em vez de um produto por chamada.
+	listThreshold = 10
+)
+
+// CachedCatalog implementa product.Catalog sobre qualquer product.Provider,
+// aplicando o cache em dois níveis, o circuit breaker e o agrupamento de
+// consultas simultâneas.
+type CachedCatalog struct {
+	provider product.Provider
+	cache    *TieredProductCache
+	cb       *CircuitBreaker
+	// callTimeout limita uma consulta ao provider. É o prazo das chamadas
+	// compartilhadas, que não herdam o contexto de quem as disparou.
+	callTimeout time.Duration
+
+	// inflight agrupa consultas simultâneas ao mesmo produto em uma única
+	// chamada ao provider.
+	inflight singleflight.Group
+}
+
+// NewCachedCatalog decora o provider. Com cache nil os produtos ficam apenas
+// na memória do processo; com breaker nil é usado um circuit breaker com
+// DefaultBreakerSettings.
+func NewCachedCatalog(provider product.Provider, cache *TieredProductCache, breaker *CircuitBreaker, callTimeout time.Duration) *CachedCatalog {
+	if cache == nil {
+		cache = NewTieredProductCache(nil, DefaultCacheOptions())
+	}
+	if breaker == nil {
+		breaker = NewCircuitBreaker(DefaultBreakerSettings())
+	}
+	return &CachedCatalog{
+		provider:    provider,
+		cache:       cache,
+		cb:          breaker,
+		callTimeout: callTimeout,
+	}
+}
+
+func (c *CachedCatalog) CircuitBreaker() *CircuitBreaker {
+	return c.cb
+}
+
+func (c *CachedCatalog) GetProduct(ctx context.Context, id uint) (product.Product, error) {
+	cached, found := c.cache.Get(ctx, id)
+	if found && !cached.Stale {
+		if cached.NotFound {
+			return product.Product{}, product.ErrNotFound
+		}
+		return cached.Product, nil
+	}
+
+	if c.cb.IsOpen() {
+		// Com o provider fora, uma cópia expirada é melhor do que nenhuma;
+		// ela é revalidada na primeira consulta depois que o breaker fechar.
+		if found {
+			return cached.Product, nil
+		}
+		return product.Product{}, ErrCircuitOpen
+	}
+
+	// A chamada compartilhada não herda o cancelamento de quem a disparou:
+	// se esse cliente desistir, os demais que esperam o mesmo produto ainda
+	// recebem o resultado. Cada chamador só deixa de esperar pelo próprio ctx;
+	// a chamada em si é limitada por callTimeout.
+	key := strconv.FormatUint(uint64(id), 10)
+	ch := c.inflight.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.callTimeout)
+		defer cancel()
+		return c.fetch(ctx, id)
+	})
+
+	select {
+	case <-ctx.Done():
+		return product.Product{}, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return product.Product{}, res.Err
+		}
+		return res.Val.(product.Product), nil
+	}
+}
+
+// fetch consulta o produto no provider, registrando o resultado no circuit
+// breaker e no cache. Produto inexistente não conta como falha.
+func (c *CachedCatalog) fetch(ctx context.Context, id uint) (product.Product, error) {
+	done, err := c.cb.Allow()
+	if err != nil {
+		return product.Product{}, err
+	}
+
+	p, err := c.provider.Fetch(ctx, id)
+	if errors.Is(err, product.ErrNotFound) {
+		done(true)
+		c.cache.SetNotFound(ctx, id)
+		return product.Product{}, product.ErrNotFound
+	}
+	if err != nil {
+		done(false)
+		return product.Product{}, err
+	}
+	done(true)
+
+	c.cache.Set(ctx, id, p)
+	return p, nil
+}
+
+// fetchAll busca o catálogo inteiro e guarda cada produto no cache.
+func (c *CachedCatalog) fetchAll(ctx context.Context) (map[uint]product.Product, error) {
+	done, err := c.cb.Allow()
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := c.provider.FetchAll(ctx)
+	if err != nil {
+		// Provider sem listagem não indica que ele esteja fora.
+		done(errors.Is(err, product.ErrListUnsupported))
+		return nil, err
+	}
+	done(true)
+
+	out := make(map[uint]product.Product, len(products))
+	for _, p := range products {
+		if p.ID == 0 {
+			continue
+		}
+		c.cache.Set(ctx, p.ID, p)
+		out[p.ID] = p
+	}
+	return out, nil
+}
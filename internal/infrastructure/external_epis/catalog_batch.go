@@ -0,0 +1,99 @@
+package external_epis
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/vinihss/aiqfome/internal/domain/product"
+)
+
+// GetProducts atende pelo cache o que estiver válido e busca o restante no
+// provider. Com muitos produtos faltando, uma única chamada à listagem
+// completa substitui as consultas individuais; os que não vierem nela, e
+// todos os demais casos, são buscados por GetProduct com no máximo
+// batchConcurrency chamadas simultâneas.
+func (c *CachedCatalog) GetProducts(ctx context.Context, ids []uint) map[uint]product.Result {
+	results := make(map[uint]product.Result, len(ids))
+
+	var missing []uint
+	for _, id := range ids {
+		if _, seen := results[id]; seen {
+			continue
+		}
+		cached, found := c.cache.Get(ctx, id)
+		if !found || cached.Stale {
+			// Reserva a posição para não repetir IDs duplicados.
+			results[id] = product.Result{}
+			missing = append(missing, id)
+			continue
+		}
+		if cached.NotFound {
+			results[id] = product.Result{Err: product.ErrNotFound}
+		} else {
+			results[id] = product.Result{Product: cached.Product}
+		}
+	}
+
+	if len(missing) >= listThreshold && !c.cb.IsOpen() {
+		missing = c.fillFromList(ctx, missing, results)
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchConcurrency)
+	for _, id := range missing {
+		wg.Add(1)
+		go func(id uint) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			p, err := c.GetProduct(ctx, id)
+
+			mutex.Lock()
+			results[id] = product.Result{Product: p, Err: err}
+			mutex.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// fillFromList preenche results com a listagem completa e devolve os IDs que
+// continuam faltando.
+func (c *CachedCatalog) fillFromList(ctx context.Context, missing []uint, results map[uint]product.Result) []uint {
+	ch := c.inflight.DoChan("all", func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.callTimeout)
+		defer cancel()
+		return c.fetchAll(ctx)
+	})
+
+	var res singleflight.Result
+	select {
+	case <-ctx.Done():
+		return missing
+	case res = <-ch:
+	}
+	if res.Err != nil {
+		if !errors.Is(res.Err, product.ErrListUnsupported) {
+			log.Printf("product catalog: listing products, falling back to single lookups: %v", res.Err)
+		}
+		return missing
+	}
+
+	all := res.Val.(map[uint]product.Product)
+	var rest []uint
+	for _, id := range missing {
+		if p, ok := all[id]; ok {
+			results[id] = product.Result{Product: p}
+		} else {
+			rest = append(rest, id)
+		}
+	}
+	return rest
+}
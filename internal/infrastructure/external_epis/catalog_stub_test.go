@@ -0,0 +1,234 @@
+package external_epis_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vinihss/aiqfome/internal/domain/product"
+	"github.com/vinihss/aiqfome/internal/infrastructure/external_epis"
+	"github.com/vinihss/aiqfome/internal/infrastructure/httpretry"
+	"github.com/vinihss/aiqfome/internal/infrastructure/productstub"
+	"github.com/vinihss/aiqfome/internal/infrastructure/productstub/productstubtest"
+)
+
+// fastRetry repete sem esperar de verdade, para os testes não dependerem do
+// backoff real.
+var fastRetry = httpretry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+// fakeClock é um relógio controlado pelo teste.
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newStubCatalog(t *testing.T, retry httpretry.Policy, breaker *external_epis.CircuitBreaker) (*external_epis.CachedCatalog, *productstubtest.Server) {
+	t.Helper()
+	srv := productstubtest.NewServer(nil)
+	t.Cleanup(srv.Close)
+	provider := external_epis.NewHTTPProvider(srv.URL, "", time.Second, retry)
+	return external_epis.NewCachedCatalog(provider, nil, breaker, 5*time.Second), srv
+}
+
+func TestCatalogRetriesBurstOf5xx(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable} {
+		catalog, srv := newStubCatalog(t, fastRetry, nil)
+		srv.Stub.FailNext(2, status)
+
+		p, err := catalog.GetProduct(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("status %d: GetProduct: %v", status, err)
+		}
+		if p.ID != 1 || p.Title == "" {
+			t.Errorf("status %d: got product %+v", status, p)
+		}
+		if got := srv.Stub.Requests(); got != 3 {
+			t.Errorf("status %d: upstream requests = %d, want 3", status, got)
+		}
+	}
+}
+
+func TestCatalogGivesUpAfterMaxAttempts(t *testing.T) {
+	catalog, srv := newStubCatalog(t, fastRetry, nil)
+	srv.Stub.FailNext(3, http.StatusServiceUnavailable)
+
+	_, err := catalog.GetProduct(context.Background(), 1)
+	if err == nil || errors.Is(err, product.ErrNotFound) {
+		t.Fatalf("GetProduct error = %v, want an upstream failure", err)
+	}
+	if got := srv.Stub.Requests(); got != 3 {
+		t.Errorf("upstream requests = %d, want 3", got)
+	}
+
+	// A rajada acabou: a consulta seguinte funciona.
+	if _, err := catalog.GetProduct(context.Background(), 1); err != nil {
+		t.Errorf("GetProduct after the burst: %v", err)
+	}
+}
+
+func TestCatalogDoesNotRetryNotFound(t *testing.T) {
+	catalog, srv := newStubCatalog(t, fastRetry, nil)
+	srv.Stub.SetFaults(productstub.Faults{Missing: []uint{7}})
+
+	if _, err := catalog.GetProduct(context.Background(), 7); !errors.Is(err, product.ErrNotFound) {
+		t.Fatalf("GetProduct error = %v, want product.ErrNotFound", err)
+	}
+	// A resposta negativa fica em cache.
+	if _, err := catalog.GetProduct(context.Background(), 7); !errors.Is(err, product.ErrNotFound) {
+		t.Fatalf("second GetProduct error = %v, want product.ErrNotFound", err)
+	}
+	if got := srv.Stub.Requests(); got != 1 {
+		t.Errorf("upstream requests = %d, want 1", got)
+	}
+}
+
+func TestCatalogBreakerOpensAndRecovers(t *testing.T) {
+	clock := newFakeClock()
+	breaker := external_epis.NewCircuitBreaker(external_epis.BreakerSettings{
+		FailureThreshold:  2,
+		Window:            time.Minute,
+		CooldownPeriod:    30 * time.Second,
+		HalfOpenMaxProbes: 1,
+		Now:               clock.Now,
+	})
+	catalog, srv := newStubCatalog(t, httpretry.Policy{MaxAttempts: 1}, breaker)
+	ctx := context.Background()
+
+	srv.Stub.FailNext(100, http.StatusServiceUnavailable)
+	for _, id := range []uint{1, 2} {
+		if _, err := catalog.GetProduct(ctx, id); err == nil {
+			t.Fatalf("GetProduct(%d) succeeded during the outage", id)
+		}
+	}
+	if got := breaker.State(); got != external_epis.StateOpen {
+		t.Fatalf("breaker state = %s, want open", got)
+	}
+
+	// Com o circuito aberto o stub não recebe mais chamadas.
+	before := srv.Stub.Requests()
+	_, err := catalog.GetProduct(ctx, 3)
+	if !errors.Is(err, external_epis.ErrCircuitOpen) || !errors.Is(err, product.ErrUnavailable) {
+		t.Fatalf("GetProduct error = %v, want ErrCircuitOpen", err)
+	}
+	if got := srv.Stub.Requests(); got != before {
+		t.Errorf("upstream requests while open = %d, want %d", got, before)
+	}
+
+	// Depois do cooldown, uma chamada de teste bem-sucedida fecha o circuito.
+	srv.Stub.SetFaults(productstub.Faults{})
+	clock.Advance(30 * time.Second)
+	if _, err := catalog.GetProduct(ctx, 3); err != nil {
+		t.Fatalf("probe GetProduct: %v", err)
+	}
+	if got := breaker.State(); got != external_epis.StateClosed {
+		t.Errorf("breaker state after probe = %s, want closed", got)
+	}
+}
+
+func TestCatalogCoalescesConcurrentMisses(t *testing.T) {
+	catalog, srv := newStubCatalog(t, httpretry.Policy{MaxAttempts: 1}, nil)
+	// A latência mantém a primeira chamada em andamento enquanto as demais
+	// chegam.
+	srv.Stub.SetFaults(productstub.Faults{Latency: 100 * time.Millisecond})
+
+	const callers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	start := make(chan struct{})
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			p, err := catalog.GetProduct(context.Background(), 4)
+			if err == nil && p.ID != 4 {
+				err = errors.New("wrong product")
+			}
+			errs <- err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("GetProduct: %v", err)
+		}
+	}
+	if got := srv.Stub.Requests(); got != 1 {
+		t.Errorf("upstream requests = %d, want 1", got)
+	}
+}
+
+func TestCatalogCancelledCallerDoesNotAbortSharedCall(t *testing.T) {
+	catalog, srv := newStubCatalog(t, httpretry.Policy{MaxAttempts: 1}, nil)
+	srv.Stub.SetFaults(productstub.Faults{Latency: 200 * time.Millisecond})
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := catalog.GetProduct(cancelled, 6)
+		first <- err
+	}()
+
+	// Espera a chamada compartilhada chegar ao stub antes de juntar os
+	// demais chamadores e cancelar o primeiro.
+	deadline := time.Now().Add(2 * time.Second)
+	for srv.Stub.Requests() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the shared call never reached the stub")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	const others = 5
+	var wg sync.WaitGroup
+	results := make(chan error, others)
+	for range others {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p, err := catalog.GetProduct(context.Background(), 6)
+			if err == nil && p.ID != 6 {
+				err = errors.New("wrong product")
+			}
+			results <- err
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller error = %v, want context.Canceled", err)
+	}
+	wg.Wait()
+	close(results)
+	for err := range results {
+		if err != nil {
+			t.Errorf("other caller: %v", err)
+		}
+	}
+	if got := srv.Stub.Requests(); got != 1 {
+		t.Errorf("upstream requests = %d, want 1", got)
+	}
+}
@@ -0,0 +1,210 @@
+package external_epis
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vinihss/aiqfome/internal/domain/product"
+)
+
+// ErrCircuitOpen é um product.ErrUnavailable: quem usa o catálogo não precisa
+// saber que a indisponibilidade veio do circuit breaker.
+var ErrCircuitOpen = fmt.Errorf("circuit breaker aberto: %w", product.ErrUnavailable)
+
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open"
+)
+
+// BreakerSettings configura o CircuitBreaker. Valores zerados assumem os de
+// DefaultBreakerSettings.
+type BreakerSettings struct {
+	// FailureThreshold é o número de falhas dentro de Window que abre o
+	// circuito.
+	FailureThreshold int
+	Window           time.Duration
+	// CooldownPeriod é quanto tempo o circuito fica aberto antes de deixar
+	// passar as primeiras chamadas de teste.
+	CooldownPeriod time.Duration
+	// HalfOpenMaxProbes limita as chamadas de teste simultâneas em half-open;
+	// o circuito fecha depois de HalfOpenMaxProbes sucessos seguidos.
+	HalfOpenMaxProbes int
+	// OnStateChange, se informado, é chamado a cada transição, fora do lock.
+	OnStateChange func(from, to string)
+	// Now permite substituir o relógio, por exemplo em testes.
+	Now func() time.Time
+}
+
+func DefaultBreakerSettings() BreakerSettings {
+	return BreakerSettings{
+		FailureThreshold:  5,
+		Window:            30 * time.Second,
+		CooldownPeriod:    time.Minute,
+		HalfOpenMaxProbes: 2,
+	}
+}
+
+// CircuitBreaker é uma máquina de estados closed → open → half-open. Em
+// closed, as falhas dos últimos Window são contadas e, ao atingir
+// FailureThreshold, o circuito abre. Depois de CooldownPeriod, até
+// HalfOpenMaxProbes chamadas passam: qualquer falha reabre o circuito e
+// HalfOpenMaxProbes sucessos o fecham.
+type CircuitBreaker struct {
+	settings BreakerSettings
+
+	mutex     sync.Mutex
+	state     string
+	failures  []time.Time // falhas dentro da janela, em ordem
+	openUntil time.Time
+	probes    int // chamadas de teste em andamento
+	successes int // sucessos em half-open
+	// generation muda a cada transição para que resultados de chamadas
+	// iniciadas em outro estado sejam ignorados.
+	generation uint64
+}
+
+func NewCircuitBreaker(settings BreakerSettings) *CircuitBreaker {
+	defaults := DefaultBreakerSettings()
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = defaults.FailureThreshold
+	}
+	if settings.Window <= 0 {
+		settings.Window = defaults.Window
+	}
+	if settings.CooldownPeriod <= 0 {
+		settings.CooldownPeriod = defaults.CooldownPeriod
+	}
+	if settings.HalfOpenMaxProbes <= 0 {
+		settings.HalfOpenMaxProbes = defaults.HalfOpenMaxProbes
+	}
+	if settings.Now == nil {
+		settings.Now = time.Now
+	}
+	return &CircuitBreaker{settings: settings, state: StateClosed}
+}
+
+// Allow reserva uma chamada. Devolve ErrCircuitOpen se ela não pode ser
+// feita; caso contrário, done deve ser chamada exatamente uma vez com o
+// resultado.
+func (cb *CircuitBreaker) Allow() (done func(success bool), err error) {
+	cb.mutex.Lock()
+	now := cb.settings.Now()
+	changed := cb.advanceLocked(now)
+
+	switch cb.state {
+	case StateOpen:
+		err = ErrCircuitOpen
+	case StateHalfOpen:
+		if cb.probes >= cb.settings.HalfOpenMaxProbes {
+			err = ErrCircuitOpen
+		} else {
+			cb.probes++
+		}
+	}
+	generation := cb.generation
+	cb.mutex.Unlock()
+	cb.notify(changed)
+
+	if err != nil {
+		return nil, err
+	}
+	var once sync.Once
+	return func(success bool) {
+		once.Do(func() { cb.record(generation, success) })
+	}, nil
+}
+
+// IsOpen informa, sem reservar uma chamada, se Allow recusaria agora.
+func (cb *CircuitBreaker) IsOpen() bool {
+	cb.mutex.Lock()
+	changed := cb.advanceLocked(cb.settings.Now())
+	open := cb.state == StateOpen ||
+		(cb.state == StateHalfOpen && cb.probes >= cb.settings.HalfOpenMaxProbes)
+	cb.mutex.Unlock()
+	cb.notify(changed)
+	return open
+}
+
+func (cb *CircuitBreaker) State() string {
+	cb.mutex.Lock()
+	changed := cb.advanceLocked(cb.settings.Now())
+	state := cb.state
+	cb.mutex.Unlock()
+	cb.notify(changed)
+	return state
+}
+
+func (cb *CircuitBreaker) record(generation uint64, success bool) {
+	cb.mutex.Lock()
+	now := cb.settings.Now()
+	changed := cb.advanceLocked(now)
+	if generation != cb.generation {
+		cb.mutex.Unlock()
+		cb.notify(changed)
+		return
+	}
+
+	switch cb.state {
+	case StateClosed:
+		if !success {
+			cb.failures = append(cb.failures, now)
+			cb.pruneLocked(now)
+			if len(cb.failures) >= cb.settings.FailureThreshold {
+				changed = cb.setStateLocked(StateOpen, now)
+			}
+		}
+	case StateHalfOpen:
+		cb.probes--
+		if !success {
+			changed = cb.setStateLocked(StateOpen, now)
+		} else if cb.successes++; cb.successes >= cb.settings.HalfOpenMaxProbes {
+			changed = cb.setStateLocked(StateClosed, now)
+		}
+	}
+	cb.mutex.Unlock()
+	cb.notify(changed)
+}
+
+// advanceLocked passa de open para half-open quando o cooldown termina.
+func (cb *CircuitBreaker) advanceLocked(now time.Time) transition {
+	if cb.state == StateOpen && !now.Before(cb.openUntil) {
+		return cb.setStateLocked(StateHalfOpen, now)
+	}
+	return transition{}
+}
+
+func (cb *CircuitBreaker) setStateLocked(state string, now time.Time) transition {
+	from := cb.state
+	cb.state = state
+	cb.generation++
+	cb.failures = cb.failures[:0]
+	cb.probes = 0
+	cb.successes = 0
+	if state == StateOpen {
+		cb.openUntil = now.Add(cb.settings.CooldownPeriod)
+	}
+	return transition{from: from, to: state}
+}
+
+func (cb *CircuitBreaker) pruneLocked(now time.Time) {
+	cutoff := now.Add(-cb.settings.Window)
+	i := 0
+	for i < len(cb.failures) && !cb.failures[i].After(cutoff) {
+		i++
+	}
+	cb.failures = cb.failures[i:]
+}
+
+// transition é uma mudança de estado a ser notificada depois de liberar o
+// lock; o valor zero indica que nada mudou.
+type transition struct {
+	from, to string
+}
+
+func (cb *CircuitBreaker) notify(t transition) {
+	if t.from != "" && cb.settings.OnStateChange != nil {
+		cb.settings.OnStateChange(t.from, t.to)
+	}
+}
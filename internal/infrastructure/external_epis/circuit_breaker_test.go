@@ -0,0 +1,227 @@
+package external_epis_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vinihss/aiqfome/internal/infrastructure/external_epis"
+)
+
+// Operações de um passo do cenário.
+const (
+	opSucceed     = "succeed"      // reserva e conclui uma chamada com sucesso
+	opFail        = "fail"         // reserva e conclui uma chamada com falha
+	opHold        = "hold"         // reserva uma chamada e a mantém em andamento
+	opReleaseOK   = "release-ok"   // conclui com sucesso a chamada mais antiga em andamento
+	opReleaseFail = "release-fail" // conclui com falha a chamada mais antiga em andamento
+	opDenied      = "denied"       // espera que Allow recuse a chamada
+	opAdvance     = "advance"      // avança o relógio em d
+)
+
+type breakerStep struct {
+	op   string
+	d    time.Duration
+	want string
+}
+
+func testBreakerSettings(clock *fakeClock) external_epis.BreakerSettings {
+	return external_epis.BreakerSettings{
+		FailureThreshold:  3,
+		Window:            10 * time.Second,
+		CooldownPeriod:    30 * time.Second,
+		HalfOpenMaxProbes: 2,
+		Now:               clock.Now,
+	}
+}
+
+func TestCircuitBreakerStateMachine(t *testing.T) {
+	const (
+		closed   = external_epis.StateClosed
+		open     = external_epis.StateOpen
+		halfOpen = external_epis.StateHalfOpen
+	)
+	// openSteps abre o circuito a partir de closed.
+	openSteps := []breakerStep{{op: opFail, want: closed}, {op: opFail, want: closed}, {op: opFail, want: open}}
+	// cooldown leva o circuito aberto a half-open.
+	cooldown := breakerStep{op: opAdvance, d: 30 * time.Second, want: halfOpen}
+
+	tests := []struct {
+		name  string
+		steps []breakerStep
+	}{
+		{
+			name:  "opens at the failure threshold within the window",
+			steps: openSteps,
+		},
+		{
+			name: "successes do not reset failures in the window",
+			steps: []breakerStep{
+				{op: opFail, want: closed},
+				{op: opSucceed, want: closed},
+				{op: opFail, want: closed},
+				{op: opFail, want: open},
+			},
+		},
+		{
+			name: "failures older than the window are forgotten",
+			steps: []breakerStep{
+				{op: opFail, want: closed},
+				{op: opFail, want: closed},
+				{op: opAdvance, d: 11 * time.Second, want: closed},
+				{op: opFail, want: closed},
+				{op: opFail, want: closed},
+				{op: opFail, want: open},
+			},
+		},
+		{
+			name: "stays open until the cooldown ends",
+			steps: append(slices.Clone(openSteps),
+				breakerStep{op: opDenied, want: open},
+				breakerStep{op: opAdvance, d: 29 * time.Second, want: open},
+				breakerStep{op: opDenied, want: open},
+				breakerStep{op: opAdvance, d: time.Second, want: halfOpen},
+			),
+		},
+		{
+			name: "limits concurrent probes in half-open",
+			steps: append(slices.Clone(openSteps), cooldown,
+				breakerStep{op: opHold, want: halfOpen},
+				breakerStep{op: opHold, want: halfOpen},
+				breakerStep{op: opDenied, want: halfOpen},
+				breakerStep{op: opReleaseOK, want: halfOpen},
+				breakerStep{op: opHold, want: halfOpen},
+				breakerStep{op: opDenied, want: halfOpen},
+			),
+		},
+		{
+			name: "reopens when a probe fails",
+			steps: append(slices.Clone(openSteps), cooldown,
+				breakerStep{op: opSucceed, want: halfOpen},
+				breakerStep{op: opFail, want: open},
+				breakerStep{op: opDenied, want: open},
+				breakerStep{op: opAdvance, d: 29 * time.Second, want: open},
+			),
+		},
+		{
+			name: "closes after the required successes",
+			steps: append(slices.Clone(openSteps), cooldown,
+				breakerStep{op: opSucceed, want: halfOpen},
+				breakerStep{op: opSucceed, want: closed},
+				breakerStep{op: opFail, want: closed},
+				breakerStep{op: opFail, want: closed},
+			),
+		},
+		{
+			name: "ignores results of calls started before a transition",
+			steps: []breakerStep{
+				{op: opHold, want: closed},
+				{op: opFail, want: closed},
+				{op: opFail, want: closed},
+				{op: opFail, want: open},
+				cooldown,
+				// A chamada reservada em closed não conta como teste.
+				{op: opReleaseFail, want: halfOpen},
+				{op: opSucceed, want: halfOpen},
+				{op: opSucceed, want: closed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			cb := external_epis.NewCircuitBreaker(testBreakerSettings(clock))
+			var held []func(bool)
+
+			for i, step := range tt.steps {
+				switch step.op {
+				case opSucceed, opFail, opHold:
+					done, err := cb.Allow()
+					if err != nil {
+						t.Fatalf("step %d (%s): Allow: %v", i, step.op, err)
+					}
+					if step.op == opHold {
+						held = append(held, done)
+					} else {
+						done(step.op == opSucceed)
+					}
+				case opReleaseOK, opReleaseFail:
+					if len(held) == 0 {
+						t.Fatalf("step %d (%s): no call in progress", i, step.op)
+					}
+					held[0](step.op == opReleaseOK)
+					held = held[1:]
+				case opDenied:
+					if _, err := cb.Allow(); !errors.Is(err, external_epis.ErrCircuitOpen) {
+						t.Fatalf("step %d: Allow error = %v, want ErrCircuitOpen", i, err)
+					}
+					if !cb.IsOpen() {
+						t.Fatalf("step %d: IsOpen = false while calls are refused", i)
+					}
+				case opAdvance:
+					clock.Advance(step.d)
+				}
+				if got := cb.State(); got != step.want {
+					t.Fatalf("step %d (%s): state = %s, want %s", i, step.op, got, step.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerOnStateChange(t *testing.T) {
+	clock := newFakeClock()
+	settings := testBreakerSettings(clock)
+	var transitions []string
+	settings.OnStateChange = func(from, to string) {
+		transitions = append(transitions, from+"->"+to)
+	}
+	cb := external_epis.NewCircuitBreaker(settings)
+
+	call := func(success bool) {
+		t.Helper()
+		done, err := cb.Allow()
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		done(success)
+	}
+
+	for range 3 {
+		call(false)
+	}
+	clock.Advance(30 * time.Second)
+	call(false) // a chamada de teste falha e reabre o circuito
+	clock.Advance(30 * time.Second)
+	call(true)
+	call(true)
+
+	want := []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}
+	if !slices.Equal(transitions, want) {
+		t.Errorf("transitions = %v, want %v", transitions, want)
+	}
+}
+
+func TestCircuitBreakerDoneIsIdempotent(t *testing.T) {
+	clock := newFakeClock()
+	cb := external_epis.NewCircuitBreaker(testBreakerSettings(clock))
+
+	done, err := cb.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		done(false)
+	}
+	if got := cb.State(); got != external_epis.StateClosed {
+		t.Errorf("state = %s, want closed: done counted more than once", got)
+	}
+}
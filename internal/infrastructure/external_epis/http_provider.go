@@ -0,0 +1,165 @@
+package external_epis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vinihss/aiqfome/internal/domain/product"
+	"github.com/vinihss/aiqfome/internal/infrastructure/httpretry"
+)
+
+// maxResponseSize limita o corpo lido do catálogo, inclusive da listagem
+// completa.
+const maxResponseSize = 4 * 1024 * 1024
+
+// ExternalProduct é o formato JSON de um produto nos catálogos HTTP e no
+// arquivo do catálogo estático.
+type ExternalProduct struct {
+	ID       uint    `json:"id"`
+	Title    string  `json:"title"`
+	Image    string  `json:"image"`
+	Price    float32 `json:"price"`
+	Category string  `json:"category,omitempty"`
+}
+
+func (p ExternalProduct) toDomain() product.Product {
+	return product.Product{
+		ID:       p.ID,
+		Title:    p.Title,
+		Image:    p.Image,
+		Price:    p.Price,
+		Category: p.Category,
+	}
+}
+
+func fromDomain(p product.Product) ExternalProduct {
+	return ExternalProduct{
+		ID:       p.ID,
+		Title:    p.Title,
+		Image:    p.Image,
+		Price:    p.Price,
+		Category: p.Category,
+	}
+}
+
+// HTTPProvider consulta um catálogo REST no formato da FakeStore:
+// GET {baseURL}/products/{id} devolve um produto e GET {baseURL}/products a
+// listagem completa, com 404 para produto inexistente. O catálogo interno do
+// aiqfome expõe o mesmo contrato e exige apenas o token no header
+// Authorization.
+type HTTPProvider struct {
+	baseURL string
+	token   string
+	http    *http.Client
+	retry   httpretry.Policy
+}
+
+// NewHTTPProvider cria o provider. timeout vale para cada tentativa feita
+// segundo retry; token vazio não envia o header Authorization.
+func NewHTTPProvider(baseURL, token string, timeout time.Duration, retry httpretry.Policy) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   3 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				MaxIdleConns:          200,
+				MaxConnsPerHost:       20,
+				MaxIdleConnsPerHost:   10,
+				IdleConnTimeout:       90 * time.Second,
+				TLSHandshakeTimeout:   3 * time.Second,
+				ExpectContinueTimeout: 1 * time.Second,
+			},
+		},
+		retry: retry,
+	}
+}
+
+func (p *HTTPProvider) Fetch(ctx context.Context, id uint) (product.Product, error) {
+	var out ExternalProduct
+	if err := p.getJSON(ctx, "/products/"+strconv.FormatUint(uint64(id), 10), &out); err != nil {
+		return product.Product{}, err
+	}
+
+	// Um objeto sem ID também é tratado como produto inexistente.
+	if out.ID == 0 {
+		return product.Product{}, product.ErrNotFound
+	}
+	return out.toDomain(), nil
+}
+
+func (p *HTTPProvider) FetchAll(ctx context.Context) ([]product.Product, error) {
+	var list []ExternalProduct
+	if err := p.getJSON(ctx, "/products", &list); err != nil {
+		if errors.Is(err, product.ErrNotFound) {
+			return nil, product.ErrListUnsupported
+		}
+		return nil, err
+	}
+
+	out := make([]product.Product, 0, len(list))
+	for _, item := range list {
+		out = append(out, item.toDomain())
+	}
+	return out, nil
+}
+
+// getJSON faz um GET em path e decodifica a resposta em out. Um 404 é
+// devolvido como product.ErrNotFound, assim como um 2xx com corpo vazio ou
+// null, que é como a FakeStore responde para IDs inexistentes.
+func (p *HTTPProvider) getJSON(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "aiqfome-favorites-service/1.0")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.retry.Do(p.http, req)
+	if err != nil {
+		return fmt.Errorf("falha ao consultar catálogo de produtos: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return product.ErrNotFound
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("erro ao consultar catálogo de produtos: status %d", resp.StatusCode)
+	}
+
+	bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("erro ao ler resposta: %w", err)
+	}
+
+	if body := bytes.TrimSpace(bodyBytes); len(body) == 0 || bytes.Equal(body, []byte("null")) {
+		return product.ErrNotFound
+	}
+
+	if err := json.Unmarshal(bodyBytes, out); err != nil {
+		return fmt.Errorf("erro ao decodificar JSON: %w", err)
+	}
+
+	return nil
+}
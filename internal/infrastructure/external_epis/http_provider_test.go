@@ -0,0 +1,92 @@
+package external_epis_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vinihss/aiqfome/internal/domain/product"
+	"github.com/vinihss/aiqfome/internal/infrastructure/external_epis"
+	"github.com/vinihss/aiqfome/internal/infrastructure/httpretry"
+)
+
+// fakeStoreServer responde como a FakeStore real: 200 com o corpo informado,
+// inclusive vazio, para qualquer caminho.
+func fakeStoreServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHTTPProviderTreatsEmptySuccessAsNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty body", ""},
+		{"whitespace", " \n"},
+		{"null", "null"},
+		{"object without id", `{"title":""}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := fakeStoreServer(t, tt.body)
+			provider := external_epis.NewHTTPProvider(srv.URL, "", time.Second, httpretry.Policy{MaxAttempts: 1})
+
+			if _, err := provider.Fetch(context.Background(), 999); !errors.Is(err, product.ErrNotFound) {
+				t.Errorf("Fetch error = %v, want product.ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestHTTPProviderDecodesProduct(t *testing.T) {
+	srv := fakeStoreServer(t, `{"id":7,"title":"Mochila","image":"https://img/7.jpg","price":109.95,"category":"bags"}`)
+	provider := external_epis.NewHTTPProvider(srv.URL, "", time.Second, httpretry.Policy{MaxAttempts: 1})
+
+	p, err := provider.Fetch(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if p.ID != 7 || p.Title != "Mochila" || p.Price != 109.95 || p.Category != "bags" {
+		t.Errorf("Fetch = %+v", p)
+	}
+}
+
+func TestHTTPProviderEmptyListIsUnsupported(t *testing.T) {
+	srv := fakeStoreServer(t, "")
+	provider := external_epis.NewHTTPProvider(srv.URL, "", time.Second, httpretry.Policy{MaxAttempts: 1})
+
+	if _, err := provider.FetchAll(context.Background()); !errors.Is(err, product.ErrListUnsupported) {
+		t.Errorf("FetchAll error = %v, want product.ErrListUnsupported", err)
+	}
+}
+
+func TestEmptySuccessDoesNotTripBreaker(t *testing.T) {
+	srv := fakeStoreServer(t, "")
+	breaker := external_epis.NewCircuitBreaker(external_epis.BreakerSettings{
+		FailureThreshold:  2,
+		Window:            time.Minute,
+		CooldownPeriod:    time.Minute,
+		HalfOpenMaxProbes: 1,
+	})
+	provider := external_epis.NewHTTPProvider(srv.URL, "", time.Second, httpretry.Policy{MaxAttempts: 1})
+	catalog := external_epis.NewCachedCatalog(provider, nil, breaker, 5*time.Second)
+
+	for id := uint(1); id <= 5; id++ {
+		if _, err := catalog.GetProduct(context.Background(), id); !errors.Is(err, product.ErrNotFound) {
+			t.Fatalf("GetProduct(%d) error = %v, want product.ErrNotFound", id, err)
+		}
+	}
+	if got := breaker.State(); got != external_epis.StateClosed {
+		t.Errorf("breaker state = %s, want closed", got)
+	}
+}
@@ -0,0 +1,187 @@
+package external_epis
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vinihss/aiqfome/internal/domain/product"
+)
+
+// CacheOptions controla a validade e o tamanho do cache de produtos.
+type CacheOptions struct {
+	// TTL é a validade de um produto encontrado.
+	TTL time.Duration
+	// NotFoundTTL é a validade, mais curta, de um produto que o catálogo
+	// respondeu como inexistente.
+	NotFoundTTL time.Duration
+	// StaleTTL é quanto tempo um produto expirado continua guardado para ser
+	// servido enquanto o catálogo estiver indisponível.
+	StaleTTL time.Duration
+	// MaxEntries limita o número de produtos em memória; os usados há mais
+	// tempo são descartados primeiro.
+	MaxEntries int
+}
+
+func DefaultCacheOptions() CacheOptions {
+	return CacheOptions{
+		TTL:         15 * time.Minute,
+		NotFoundTTL: time.Minute,
+		StaleTTL:    time.Hour,
+		MaxEntries:  10000,
+	}
+}
+
+// janitorInterval é a frequência com que entradas vencidas são removidas.
+const janitorInterval = time.Minute
+
+// CachedProduct é o resultado de uma consulta ao cache. NotFound indica um
+// produto inexistente guardado por negative caching; Stale, um produto já
+// expirado que só deve ser usado se o catálogo estiver fora.
+type CachedProduct struct {
+	Product  product.Product
+	NotFound bool
+	Stale    bool
+}
+
+type cacheEntry struct {
+	id        uint
+	product   product.Product
+	notFound  bool
+	expiresAt time.Time
+	// removeAt é quando a entrada deixa de poder ser servida, mesmo como
+	// stale, e pode ser descartada.
+	removeAt time.Time
+}
+
+// ProductCache é o cache em memória (L1) de produtos, com expiração por
+// entrada e limite de tamanho LRU.
+type ProductCache struct {
+	mutex sync.Mutex
+	items map[uint]*list.Element
+	lru   *list.List
+	opts  CacheOptions
+	now   func() time.Time
+}
+
+func NewProductCache(opts CacheOptions) *ProductCache {
+	return &ProductCache{
+		items: make(map[uint]*list.Element),
+		lru:   list.New(),
+		opts:  opts,
+		now:   time.Now,
+	}
+}
+
+func (c *ProductCache) Get(id uint) (CachedProduct, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	el, ok := c.items[id]
+	if !ok {
+		return CachedProduct{}, false
+	}
+	e := el.Value.(*cacheEntry)
+	now := c.now()
+	if !now.Before(e.removeAt) {
+		c.removeLocked(el)
+		return CachedProduct{}, false
+	}
+
+	c.lru.MoveToFront(el)
+	return CachedProduct{
+		Product:  e.product,
+		NotFound: e.notFound,
+		Stale:    !now.Before(e.expiresAt),
+	}, true
+}
+
+func (c *ProductCache) Set(id uint, p product.Product) {
+	now := c.now()
+	expiresAt := now.Add(c.opts.TTL)
+	c.put(&cacheEntry{
+		id:        id,
+		product:   p,
+		expiresAt: expiresAt,
+		removeAt:  expiresAt.Add(c.opts.StaleTTL),
+	})
+}
+
+// SetNotFound guarda que o produto não existe. Entradas negativas nunca são
+// servidas como stale.
+func (c *ProductCache) SetNotFound(id uint) {
+	expiresAt := c.now().Add(c.opts.NotFoundTTL)
+	c.put(&cacheEntry{id: id, notFound: true, expiresAt: expiresAt, removeAt: expiresAt})
+}
+
+func (c *ProductCache) Delete(id uint) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if el, ok := c.items[id]; ok {
+		c.removeLocked(el)
+	}
+}
+
+func (c *ProductCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.items = make(map[uint]*list.Element)
+	c.lru.Init()
+}
+
+func (c *ProductCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lru.Len()
+}
+
+// RunJanitor remove periodicamente as entradas que não podem mais ser
+// servidas. Bloqueia até ctx ser cancelado.
+func (c *ProductCache) RunJanitor(ctx context.Context) error {
+	ticker := time.NewTicker(janitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.removeExpired()
+		}
+	}
+}
+
+func (c *ProductCache) removeExpired() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	for el := c.lru.Back(); el != nil; {
+		prev := el.Prev()
+		if !now.Before(el.Value.(*cacheEntry).removeAt) {
+			c.removeLocked(el)
+		}
+		el = prev
+	}
+}
+
+func (c *ProductCache) put(e *cacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if el, ok := c.items[e.id]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.items[e.id] = c.lru.PushFront(e)
+
+	for c.opts.MaxEntries > 0 && c.lru.Len() > c.opts.MaxEntries {
+		c.removeLocked(c.lru.Back())
+	}
+}
+
+func (c *ProductCache) removeLocked(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.items, el.Value.(*cacheEntry).id)
+}
@@ -0,0 +1,211 @@
+package external_epis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/vinihss/aiqfome/internal/domain/product"
+)
+
+const (
+	productCacheKey    = "products:%d"
+	productCachePrefix = "products:"
+	// productInvalidateChannel recebe o ID do produto a remover, ou "*" para
+	// esvaziar o cache inteiro.
+	productInvalidateChannel = "products:invalidate"
+	purgeAllMessage          = "*"
+
+	productCacheTimeout = 200 * time.Millisecond
+)
+
+// TieredProductCache combina o ProductCache em memória (L1) com o Redis (L2),
+// compartilhado entre as réplicas. Invalidações são publicadas em um canal
+// pub/sub para que todas as instâncias descartem a cópia local. Sem Redis,
+// funciona apenas com o L1.
+type TieredProductCache struct {
+	l1   *ProductCache
+	rdb  *redis.Client
+	opts CacheOptions
+}
+
+// redisEntry é o formato gravado no L2. Produtos inexistentes são gravados
+// com NotFound e a validade mais curta de CacheOptions.NotFoundTTL.
+type redisEntry struct {
+	Product  ExternalProduct `json:"product"`
+	NotFound bool            `json:"not_found,omitempty"`
+}
+
+func NewTieredProductCache(rdb *redis.Client, opts CacheOptions) *TieredProductCache {
+	return &TieredProductCache{l1: NewProductCache(opts), rdb: rdb, opts: opts}
+}
+
+// Get consulta o L1 e depois o Redis, promovendo para o L1 o que encontrar
+// lá. Uma entrada stale do L1 só é devolvida se o Redis também não tiver o
+// produto. Erros do Redis são tratados como miss.
+func (c *TieredProductCache) Get(ctx context.Context, id uint) (CachedProduct, bool) {
+	local, found := c.l1.Get(id)
+	if found && !local.Stale {
+		return local, true
+	}
+	if c.rdb == nil {
+		return local, found
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, productCacheTimeout)
+	defer cancel()
+	data, err := c.rdb.Get(ctx, fmt.Sprintf(productCacheKey, id)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("product cache: reading product %d: %v", id, err)
+		}
+		return local, found
+	}
+
+	var entry redisEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		log.Printf("product cache: decoding product %d: %v", id, err)
+		return local, found
+	}
+	p := entry.Product.toDomain()
+	if entry.NotFound {
+		c.l1.SetNotFound(id)
+	} else {
+		c.l1.Set(id, p)
+	}
+	return CachedProduct{Product: p, NotFound: entry.NotFound}, true
+}
+
+func (c *TieredProductCache) Set(ctx context.Context, id uint, p product.Product) {
+	c.l1.Set(id, p)
+	c.setRedis(ctx, id, redisEntry{Product: fromDomain(p)}, c.opts.TTL)
+}
+
+// SetNotFound guarda por pouco tempo que o produto não existe, evitando que
+// IDs inválidos repetidos cheguem ao catálogo.
+func (c *TieredProductCache) SetNotFound(ctx context.Context, id uint) {
+	c.l1.SetNotFound(id)
+	c.setRedis(ctx, id, redisEntry{NotFound: true}, c.opts.NotFoundTTL)
+}
+
+// RunJanitor remove do L1 as entradas vencidas; no Redis a expiração é
+// feita pelo próprio TTL das chaves.
+func (c *TieredProductCache) RunJanitor(ctx context.Context) error {
+	return c.l1.RunJanitor(ctx)
+}
+
+func (c *TieredProductCache) setRedis(ctx context.Context, id uint, entry redisEntry, ttl time.Duration) {
+	if c.rdb == nil {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, productCacheTimeout)
+	defer cancel()
+	if err := c.rdb.Set(ctx, fmt.Sprintf(productCacheKey, id), data, ttl).Err(); err != nil {
+		log.Printf("product cache: writing product %d: %v", id, err)
+	}
+}
+
+// Invalidate remove o produto do Redis e avisa todas as instâncias, inclusive
+// esta, para que o descartem do L1.
+func (c *TieredProductCache) Invalidate(ctx context.Context, id uint) error {
+	c.l1.Delete(id)
+	if c.rdb == nil {
+		return nil
+	}
+	if err := c.rdb.Del(ctx, fmt.Sprintf(productCacheKey, id)).Err(); err != nil {
+		return err
+	}
+	return c.rdb.Publish(ctx, productInvalidateChannel, strconv.FormatUint(uint64(id), 10)).Err()
+}
+
+// Purge esvazia o cache de produtos em todas as instâncias.
+func (c *TieredProductCache) Purge(ctx context.Context) error {
+	c.l1.Clear()
+	if c.rdb == nil {
+		return nil
+	}
+
+	iter := c.rdb.Scan(ctx, 0, productCachePrefix+"[0-9]*", 500).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == 500 {
+			if err := c.rdb.Unlink(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		if err := c.rdb.Unlink(ctx, keys...).Err(); err != nil {
+			return err
+		}
+	}
+	return c.rdb.Publish(ctx, productInvalidateChannel, purgeAllMessage).Err()
+}
+
+// Listen assina o canal de invalidação e aplica no L1 as remoções feitas por
+// qualquer instância. Bloqueia até ctx ser cancelado. Mensagens publicadas
+// enquanto a conexão estiver caída são perdidas; o L1 é esvaziado quando a
+// assinatura é restabelecida para não servir dados antigos.
+func (c *TieredProductCache) Listen(ctx context.Context) error {
+	if c.rdb == nil {
+		<-ctx.Done()
+		return nil
+	}
+
+	pubsub := c.rdb.Subscribe(ctx, productInvalidateChannel)
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Printf("product cache: invalidation channel: %v", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind == "subscribe" {
+				c.l1.Clear()
+			}
+		case *redis.Message:
+			c.apply(m.Payload)
+		}
+	}
+}
+
+func (c *TieredProductCache) apply(payload string) {
+	if payload == purgeAllMessage {
+		c.l1.Clear()
+		return
+	}
+	id, err := strconv.ParseUint(payload, 10, 64)
+	if err != nil {
+		log.Printf("product cache: ignoring invalidation message %q", payload)
+		return
+	}
+	c.l1.Delete(uint(id))
+}
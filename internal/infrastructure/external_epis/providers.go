@@ -0,0 +1,89 @@
+package external_epis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vinihss/aiqfome/internal/domain/product"
+	"github.com/vinihss/aiqfome/internal/infrastructure/httpretry"
+)
+
+// Nomes dos providers registrados por padrão, usados em PRODUCT_PROVIDER.
+const (
+	ProviderFakeStore = "fakestore"
+	ProviderAiqfome   = "aiqfome"
+	ProviderStatic    = "static"
+)
+
+// ProviderConfig reúne o que os providers podem precisar; cada um usa apenas
+// os campos que lhe dizem respeito.
+type ProviderConfig struct {
+	BaseURL     string
+	Token       string
+	CatalogFile string
+	Timeout     time.Duration
+	Retry       httpretry.Policy
+}
+
+type ProviderFactory func(cfg ProviderConfig) (product.Provider, error)
+
+var (
+	providersMutex sync.RWMutex
+	providers      = map[string]ProviderFactory{
+		ProviderFakeStore: newHTTPProviderFactory(false),
+		ProviderAiqfome:   newHTTPProviderFactory(true),
+		ProviderStatic: func(cfg ProviderConfig) (product.Provider, error) {
+			if cfg.CatalogFile == "" {
+				return nil, fmt.Errorf("provider %q requires a catalog file", ProviderStatic)
+			}
+			return NewStaticProvider(cfg.CatalogFile)
+		},
+	}
+)
+
+func newHTTPProviderFactory(withToken bool) ProviderFactory {
+	return func(cfg ProviderConfig) (product.Provider, error) {
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("product provider requires a base URL")
+		}
+		token := ""
+		if withToken {
+			token = cfg.Token
+		}
+		return NewHTTPProvider(cfg.BaseURL, token, cfg.Timeout, cfg.Retry), nil
+	}
+}
+
+// RegisterProvider disponibiliza um provider para NewProvider, substituindo
+// outro de mesmo nome.
+func RegisterProvider(name string, factory ProviderFactory) {
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+	providers[name] = factory
+}
+
+// ProviderNames lista os providers registrados em ordem alfabética.
+func ProviderNames() []string {
+	providersMutex.RLock()
+	defer providersMutex.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewProvider cria o provider registrado com o nome informado.
+func NewProvider(name string, cfg ProviderConfig) (product.Provider, error) {
+	providersMutex.RLock()
+	factory, ok := providers[name]
+	providersMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown product provider %q (available: %s)", name, strings.Join(ProviderNames(), ", "))
+	}
+	return factory(cfg)
+}
@@ -0,0 +1,59 @@
+package external_epis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/vinihss/aiqfome/internal/domain/product"
+)
+
+// StaticProvider serve um catálogo fixo carregado de um arquivo JSON com um
+// array de produtos no mesmo formato da FakeStore. Serve para ambientes de
+// desenvolvimento e testes que não devem depender de rede.
+type StaticProvider struct {
+	products map[uint]product.Product
+	ordered  []product.Product
+}
+
+// NewStaticProvider lê o catálogo de path. IDs repetidos ou iguais a zero são
+// rejeitados para que erros no arquivo apareçam na inicialização.
+func NewStaticProvider(path string) (*StaticProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lendo catálogo estático: %w", err)
+	}
+
+	var list []ExternalProduct
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("decodificando catálogo estático %s: %w", path, err)
+	}
+
+	p := &StaticProvider{products: make(map[uint]product.Product, len(list))}
+	for i, item := range list {
+		if item.ID == 0 {
+			return nil, fmt.Errorf("catálogo estático %s: produto na posição %d sem id", path, i)
+		}
+		if _, dup := p.products[item.ID]; dup {
+			return nil, fmt.Errorf("catálogo estático %s: id %d repetido", path, item.ID)
+		}
+		p.products[item.ID] = item.toDomain()
+		p.ordered = append(p.ordered, item.toDomain())
+	}
+	return p, nil
+}
+
+func (p *StaticProvider) Fetch(_ context.Context, id uint) (product.Product, error) {
+	found, ok := p.products[id]
+	if !ok {
+		return product.Product{}, product.ErrNotFound
+	}
+	return found, nil
+}
+
+func (p *StaticProvider) FetchAll(context.Context) ([]product.Product, error) {
+	out := make([]product.Product, len(p.ordered))
+	copy(out, p.ordered)
+	return out, nil
+}
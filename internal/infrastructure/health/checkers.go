@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type dbChecker struct {
+	db *gorm.DB
+}
+
+func NewDBChecker(db *gorm.DB) HealthChecker {
+	return &dbChecker{db: db}
+}
+
+func (c *dbChecker) Name() string { return "postgres" }
+
+func (c *dbChecker) Check(ctx context.Context) error {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+type redisChecker struct {
+	rdb *redis.Client
+}
+
+func NewRedisChecker(rdb *redis.Client) HealthChecker {
+	return &redisChecker{rdb: rdb}
+}
+
+func (c *redisChecker) Name() string { return "redis" }
+
+func (c *redisChecker) Check(ctx context.Context) error {
+	return c.rdb.Ping(ctx).Err()
+}
+
+// StateReporter é implementado por circuit breakers que informam seu estado
+// atual ("closed", "open" ou "half-open").
+type StateReporter interface {
+	State() string
+}
+
+type circuitBreakerChecker struct {
+	name    string
+	breaker StateReporter
+}
+
+// NewCircuitBreakerChecker reporta o estado de um circuit breaker. Um circuito
+// aberto deixa o serviço degradado, mas não indisponível: apenas as operações
+// que dependem da API externa falham.
+func NewCircuitBreakerChecker(name string, breaker StateReporter) DetailedChecker {
+	return &circuitBreakerChecker{name: name, breaker: breaker}
+}
+
+func (c *circuitBreakerChecker) Name() string { return c.name }
+
+func (c *circuitBreakerChecker) Check(context.Context) error {
+	if state := c.breaker.State(); state != "closed" {
+		return fmt.Errorf("%w: circuit breaker %s", ErrDegraded, state)
+	}
+	return nil
+}
+
+func (c *circuitBreakerChecker) Details() map[string]string {
+	return map[string]string{"circuit_breaker": c.breaker.State()}
+}
+
+type cacheStatsChecker struct {
+	name  string
+	stats func() (hits, misses, errors uint64)
+}
+
+// NewCacheStatsChecker expõe os contadores de um cache no /readyz. O cache
+// nunca tira a instância do balanceamento: sem ele as leituras vão ao banco.
+func NewCacheStatsChecker(name string, stats func() (hits, misses, errors uint64)) DetailedChecker {
+	return &cacheStatsChecker{name: name, stats: stats}
+}
+
+func (c *cacheStatsChecker) Name() string { return c.name }
+
+func (c *cacheStatsChecker) Check(context.Context) error { return nil }
+
+func (c *cacheStatsChecker) Details() map[string]string {
+	hits, misses, errors := c.stats()
+	ratio := 0.0
+	if hits+misses > 0 {
+		ratio = float64(hits) / float64(hits+misses)
+	}
+	return map[string]string{
+		"hits":      strconv.FormatUint(hits, 10),
+		"misses":    strconv.FormatUint(misses, 10),
+		"errors":    strconv.FormatUint(errors, 10),
+		"hit_ratio": strconv.FormatFloat(ratio, 'f', 3, 64),
+	}
+}
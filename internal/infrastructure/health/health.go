@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// ErrDegraded indica que a dependência responde, mas com funcionalidade
+// reduzida. Checkers devem envolvê-lo (fmt.Errorf("%w: ...", ErrDegraded))
+// quando a falha não deve tirar a instância do balanceamento.
+var ErrDegraded = errors.New("degraded")
+
+// HealthChecker verifica uma dependência do serviço. Qualquer componente pode
+// se registrar no Registry implementando esta interface.
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// DetailedChecker é implementado por checkers que expõem informações extras,
+// como o estado de um circuit breaker.
+type DetailedChecker interface {
+	HealthChecker
+	Details() map[string]string
+}
+
+type Result struct {
+	Status    Status            `json:"status"`
+	LatencyMs int64             `json:"latency_ms"`
+	Error     string            `json:"error,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+type Registry struct {
+	mutex    sync.RWMutex
+	checkers []HealthChecker
+	timeout  time.Duration
+}
+
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+func (r *Registry) Register(checker HealthChecker) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.checkers = append(r.checkers, checker)
+}
+
+// Run executa todos os checkers em paralelo, cada um limitado pelo timeout do
+// registro. O status geral é o pior status individual.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mutex.RLock()
+	checkers := append([]HealthChecker(nil), r.checkers...)
+	r.mutex.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(checkers))}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, checker := range checkers {
+		wg.Add(1)
+		go func(checker HealthChecker) {
+			defer wg.Done()
+			result := r.run(ctx, checker)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Checks[checker.Name()] = result
+			if severity(result.Status) > severity(report.Status) {
+				report.Status = result.Status
+			}
+		}(checker)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, checker HealthChecker) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := Result{
+		Status:    StatusUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		if errors.Is(err, ErrDegraded) {
+			result.Status = StatusDegraded
+		}
+		result.Error = err.Error()
+	}
+	if detailed, ok := checker.(DetailedChecker); ok {
+		result.Details = detailed.Details()
+	}
+	return result
+}
+
+func severity(s Status) int {
+	switch s {
+	case StatusDown:
+		return 2
+	case StatusDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
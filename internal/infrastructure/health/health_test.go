@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// stubChecker responde com o erro configurado, ou espera o contexto acabar
+// quando hang é verdadeiro.
+type stubChecker struct {
+	name string
+	err  error
+	hang bool
+}
+
+func (s stubChecker) Name() string { return s.name }
+
+func (s stubChecker) Check(ctx context.Context) error {
+	if s.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return s.err
+}
+
+type detailedStub struct {
+	stubChecker
+	details map[string]string
+}
+
+func (s detailedStub) Details() map[string]string { return s.details }
+
+func TestRegistryReportsTheWorstStatus(t *testing.T) {
+	up := stubChecker{name: "postgres"}
+	degraded := stubChecker{name: "products_api", err: fmt.Errorf("%w: circuit breaker open", ErrDegraded)}
+	down := stubChecker{name: "redis", err: errors.New("connection refused")}
+
+	tests := []struct {
+		name     string
+		checkers []HealthChecker
+		want     Status
+	}{
+		{"no checkers", nil, StatusUp},
+		{"all up", []HealthChecker{up}, StatusUp},
+		{"degraded wins over up", []HealthChecker{up, degraded}, StatusDegraded},
+		{"down wins over degraded", []HealthChecker{degraded, down, up}, StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(time.Second)
+			for _, c := range tt.checkers {
+				registry.Register(c)
+			}
+
+			report := registry.Run(context.Background())
+			if report.Status != tt.want {
+				t.Errorf("Status = %q, want %q", report.Status, tt.want)
+			}
+			if len(report.Checks) != len(tt.checkers) {
+				t.Errorf("Checks = %v, want one result per checker", report.Checks)
+			}
+		})
+	}
+}
+
+func TestRegistryClassifiesCheckErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    Status
+		wantErr string
+	}{
+		{"nil error", nil, StatusUp, ""},
+		{"wrapped ErrDegraded", fmt.Errorf("%w: circuit breaker open", ErrDegraded), StatusDegraded, "degraded: circuit breaker open"},
+		{"ErrDegraded wrapped twice", fmt.Errorf("products: %w", fmt.Errorf("%w: half-open", ErrDegraded)), StatusDegraded, "products: degraded: half-open"},
+		{"any other error", errors.New("connection refused"), StatusDown, "connection refused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(time.Second)
+			registry.Register(stubChecker{name: "dep", err: tt.err})
+
+			result := registry.Run(context.Background()).Checks["dep"]
+			if result.Status != tt.want || result.Error != tt.wantErr {
+				t.Errorf("result = %+v, want status %q and error %q", result, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistryLimitsEachCheckByTheTimeout(t *testing.T) {
+	registry := NewRegistry(20 * time.Millisecond)
+	registry.Register(stubChecker{name: "stuck", hang: true})
+	registry.Register(stubChecker{name: "postgres"})
+
+	start := time.Now()
+	report := registry.Run(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Run took %v, want it bounded by the check timeout", elapsed)
+	}
+	if report.Status != StatusDown {
+		t.Errorf("Status = %q, want %q", report.Status, StatusDown)
+	}
+	stuck := report.Checks["stuck"]
+	if stuck.Status != StatusDown || stuck.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("stuck = %+v, want down with a deadline error", stuck)
+	}
+	if got := report.Checks["postgres"].Status; got != StatusUp {
+		t.Errorf("postgres = %q, want %q; a slow check must not affect the others", got, StatusUp)
+	}
+}
+
+func TestRegistryIncludesDetails(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register(detailedStub{
+		stubChecker: stubChecker{name: "products_api"},
+		details:     map[string]string{"circuit_breaker": "closed"},
+	})
+
+	result := registry.Run(context.Background()).Checks["products_api"]
+	if result.Details["circuit_breaker"] != "closed" {
+		t.Errorf("Details = %v, want the circuit breaker state", result.Details)
+	}
+}
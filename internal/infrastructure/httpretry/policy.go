@@ -0,0 +1,167 @@
+// Package httpretry implementa uma política de novas tentativas para
+// clientes HTTP de saída, com backoff exponencial, jitter e suporte ao
+// header Retry-After.
+package httpretry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Policy define quando e quanto esperar entre as tentativas. Valores zerados
+// assumem os de DefaultPolicy.
+type Policy struct {
+	// MaxAttempts inclui a primeira tentativa; 1 desliga as repetições.
+	MaxAttempts int
+	// BaseDelay é a espera antes da segunda tentativa; cada nova tentativa
+	// dobra a espera, limitada a MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// RetryableStatus lista os status que justificam uma nova tentativa; nil
+	// usa a lista padrão e uma lista vazia só repete em erros de rede.
+	RetryableStatus []int
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (p Policy) withDefaults() Policy {
+	defaults := DefaultPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaults.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaults.MaxDelay
+	}
+	if p.RetryableStatus == nil {
+		p.RetryableStatus = defaults.RetryableStatus
+	}
+	return p
+}
+
+// MaxDuration é o tempo máximo que Do pode levar quando cada tentativa dura
+// no máximo perAttempt. Serve para definir o prazo de chamadas que não
+// herdam o contexto de quem as disparou.
+func (p Policy) MaxDuration(perAttempt time.Duration) time.Duration {
+	p = p.withDefaults()
+	return time.Duration(p.MaxAttempts)*perAttempt + time.Duration(p.MaxAttempts-1)*p.MaxDelay
+}
+
+// Do envia req pelo client, repetindo em erros de rede e nos status de
+// RetryableStatus. Cada tentativa usa uma cópia da requisição, então
+// requisições com corpo precisam de GetBody (como as criadas por
+// http.NewRequest). Um Retry-After é respeitado até MaxDelay: pedidos de
+// espera maiores são encurtados para MaxDelay, para não segurar o chamador.
+// A espera respeita o contexto da requisição: se ele for cancelado, ou se o
+// prazo não comportar a próxima tentativa, Do desiste e devolve o último
+// resultado. A resposta devolvida, mesmo com status de erro, deve ser
+// fechada pelo chamador.
+func (p Policy) Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	p = p.withDefaults()
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := cloneRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(attemptReq)
+		if !p.shouldRetry(ctx, resp, err) || attempt == p.MaxAttempts {
+			return resp, err
+		}
+
+		delay := p.backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				delay = min(after, p.MaxDelay)
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+		if resp != nil {
+			// Drena o corpo para que a conexão volte ao pool.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (p Policy) shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled)
+	}
+	return slices.Contains(p.RetryableStatus, resp.StatusCode)
+}
+
+// backoff devolve a espera após a tentativa informada, com jitter entre
+// metade e o valor cheio para que clientes não repitam em sincronia.
+func (p Policy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// retryAfter interpreta o header Retry-After em segundos ou como data HTTP.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return clone, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("httpretry: request body cannot be replayed, set GetBody")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
@@ -0,0 +1,93 @@
+package httpretry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingServer responde status às primeiras failures requisições e 200 às
+// seguintes, contando as chamadas.
+func failingServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func get(t *testing.T, p Policy, ctx context.Context, url string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := p.Do(http.DefaultClient, req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestDoRetriesDefaultStatuses(t *testing.T) {
+	for _, status := range []int{429, 500, 502, 503, 504} {
+		srv, calls := failingServer(t, 2, status, nil)
+		p := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+		resp := get(t, p, context.Background(), srv.URL)
+		if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+			t.Errorf("status %d: got %d after %d calls, want 200 after 3", status, resp.StatusCode, calls.Load())
+		}
+	}
+}
+
+func TestDoUsesConfiguredStatuses(t *testing.T) {
+	srv, calls := failingServer(t, 1, http.StatusInternalServerError, nil)
+	p := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, RetryableStatus: []int{}}
+
+	resp := get(t, p, context.Background(), srv.URL)
+	if resp.StatusCode != http.StatusInternalServerError || calls.Load() != 1 {
+		t.Errorf("got %d after %d calls, want 500 after 1", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestDoCapsRetryAfterAtMaxDelay(t *testing.T) {
+	srv, calls := failingServer(t, 1, http.StatusServiceUnavailable, http.Header{"Retry-After": {"30"}})
+	p := Policy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 20 * time.Millisecond}
+
+	start := time.Now()
+	resp := get(t, p, context.Background(), srv.URL)
+	elapsed := time.Since(start)
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Fatalf("got %d after %d calls, want 200 after 2", resp.StatusCode, calls.Load())
+	}
+	if elapsed < 20*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("waited %s, want about MaxDelay", elapsed)
+	}
+}
+
+func TestDoGivesUpWhenDeadlineIsTooShort(t *testing.T) {
+	srv, calls := failingServer(t, 1, http.StatusServiceUnavailable, http.Header{"Retry-After": {"1"}})
+	p := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	resp := get(t, p, ctx, srv.URL)
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("got %d after %d calls, want 503 after 1", resp.StatusCode, calls.Load())
+	}
+}
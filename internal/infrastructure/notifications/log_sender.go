@@ -0,0 +1,59 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/alert"
+)
+
+// LogSender grava cada alerta como uma linha JSON em w, que pode ser o log
+// do processo ou um arquivo. Serve para desenvolvimento e testes enquanto não
+// há canais reais de entrega.
+type LogSender struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+func NewLogSender(w io.Writer) *LogSender {
+	return &LogSender{w: w}
+}
+
+func (s *LogSender) Name() string {
+	return "log"
+}
+
+type logLine struct {
+	Time       time.Time `json:"time"`
+	AlertID    uint      `json:"alert_id"`
+	CustomerID uint      `json:"customer_id"`
+	ProductID  uint      `json:"product_id"`
+	Kind       string    `json:"kind"`
+	Threshold  float32   `json:"threshold,omitempty"`
+	OldPrice   float32   `json:"old_price"`
+	NewPrice   float32   `json:"new_price"`
+}
+
+func (s *LogSender) Send(_ context.Context, a domain.Alert) error {
+	data, err := json.Marshal(logLine{
+		Time:       a.CreatedAt,
+		AlertID:    a.ID,
+		CustomerID: a.CustomerID,
+		ProductID:  a.ProductID,
+		Kind:       a.Kind,
+		Threshold:  a.Threshold,
+		OldPrice:   a.OldPrice,
+		NewPrice:   a.NewPrice,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
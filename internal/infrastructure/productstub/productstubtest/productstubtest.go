@@ -0,0 +1,28 @@
+// Package productstubtest sobe o catálogo simulado de productstub em um
+// httptest.Server. Deve ser importado apenas por testes.
+package productstubtest
+
+import (
+	"net/http/httptest"
+
+	"github.com/vinihss/aiqfome/internal/infrastructure/external_epis"
+	"github.com/vinihss/aiqfome/internal/infrastructure/productstub"
+)
+
+// Server é o catálogo simulado rodando em uma porta local livre. Sua URL
+// serve como PRODUCT_SERVICE_URL ou como base de um
+// external_epis.HTTPProvider.
+type Server struct {
+	*httptest.Server
+	Stub *productstub.Server
+}
+
+// NewServer sobe o catálogo. Com products nil são usadas as
+// productstub.DefaultFixtures. Quem chama deve fechar o servidor com Close.
+func NewServer(products []external_epis.ExternalProduct) *Server {
+	if products == nil {
+		products = productstub.DefaultFixtures()
+	}
+	stub := productstub.New(products)
+	return &Server{Server: httptest.NewServer(stub), Stub: stub}
+}
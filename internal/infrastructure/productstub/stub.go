@@ -0,0 +1,267 @@
+// Package productstub simula um catálogo de produtos com o contrato da
+// FakeStore (GET /products e GET /products/{id}) para desenvolvimento local
+// e testes sem acesso à internet. Latência, rajadas de erros 5xx e produtos
+// inexistentes podem ser injetados na criação ou em tempo de execução.
+package productstub
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vinihss/aiqfome/internal/infrastructure/external_epis"
+)
+
+//go:embed fixtures/products.json
+var defaultFixtures []byte
+
+// Faults descreve as falhas injetadas nas rotas de produtos.
+type Faults struct {
+	// Latency é somada a cada resposta.
+	Latency time.Duration
+	// FailNext faz as próximas FailNext requisições responderem FailStatus.
+	FailNext int
+	// FailStatus é o status das falhas injetadas; zero equivale a 503.
+	FailStatus int
+	// Missing lista IDs respondidos com 404 e omitidos da listagem, mesmo
+	// que estejam nas fixtures.
+	Missing []uint
+}
+
+// Server é o handler HTTP do catálogo simulado.
+type Server struct {
+	mutex    sync.Mutex
+	products map[uint]external_epis.ExternalProduct
+	ordered  []external_epis.ExternalProduct
+	faults   Faults
+	requests atomic.Int64
+
+	mux *http.ServeMux
+}
+
+// DefaultFixtures devolve os produtos embutidos no binário, uma cópia dos
+// 20 primeiros produtos da FakeStore.
+func DefaultFixtures() []external_epis.ExternalProduct {
+	products, err := parseFixtures(defaultFixtures)
+	if err != nil {
+		panic(fmt.Sprintf("productstub: embedded fixtures: %v", err))
+	}
+	return products
+}
+
+// LoadFixtures lê um arquivo JSON com um array de produtos. Com path vazio
+// devolve DefaultFixtures.
+func LoadFixtures(path string) ([]external_epis.ExternalProduct, error) {
+	if path == "" {
+		return DefaultFixtures(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	products, err := parseFixtures(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return products, nil
+}
+
+func parseFixtures(data []byte) ([]external_epis.ExternalProduct, error) {
+	var products []external_epis.ExternalProduct
+	if err := json.Unmarshal(data, &products); err != nil {
+		return nil, err
+	}
+	seen := make(map[uint]bool, len(products))
+	for i, p := range products {
+		if p.ID == 0 {
+			return nil, fmt.Errorf("product at position %d has no id", i)
+		}
+		if seen[p.ID] {
+			return nil, fmt.Errorf("duplicate product id %d", p.ID)
+		}
+		seen[p.ID] = true
+	}
+	return products, nil
+}
+
+func New(products []external_epis.ExternalProduct) *Server {
+	s := &Server{
+		products: make(map[uint]external_epis.ExternalProduct, len(products)),
+		ordered:  slices.Clone(products),
+		mux:      http.NewServeMux(),
+	}
+	for _, p := range products {
+		s.products[p.ID] = p
+	}
+
+	s.mux.HandleFunc("GET /products", s.list)
+	s.mux.HandleFunc("GET /products/{id}", s.get)
+	s.mux.HandleFunc("GET /_stub/faults", s.getFaults)
+	s.mux.HandleFunc("PUT /_stub/faults", s.putFaults)
+	s.mux.HandleFunc("GET /_stub/stats", s.stats)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// SetFaults substitui as falhas injetadas.
+func (s *Server) SetFaults(f Faults) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.faults = f
+	s.faults.Missing = slices.Clone(f.Missing)
+}
+
+// Faults devolve as falhas configuradas no momento, com FailNext já
+// descontado das falhas servidas.
+func (s *Server) Faults() Faults {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	f := s.faults
+	f.Missing = slices.Clone(f.Missing)
+	return f
+}
+
+// FailNext agenda uma rajada de n respostas com o status informado.
+func (s *Server) FailNext(n, status int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.faults.FailNext = n
+	s.faults.FailStatus = status
+}
+
+// Requests conta as requisições recebidas nas rotas de produtos, inclusive
+// as que falharam por injeção.
+func (s *Server) Requests() int64 {
+	return s.requests.Load()
+}
+
+// inject aplica latência e rajadas de erro. Devolve false se a resposta já
+// foi escrita.
+func (s *Server) inject(w http.ResponseWriter, r *http.Request) bool {
+	s.requests.Add(1)
+
+	s.mutex.Lock()
+	latency := s.faults.Latency
+	status := 0
+	if s.faults.FailNext > 0 {
+		s.faults.FailNext--
+		status = s.faults.FailStatus
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+	}
+	s.mutex.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+	if status != 0 {
+		writeJSON(w, status, map[string]string{"error": "injected failure"})
+		return false
+	}
+	return true
+}
+
+func (s *Server) isMissing(id uint) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return slices.Contains(s.faults.Missing, id)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	if !s.inject(w, r) {
+		return
+	}
+	out := make([]external_epis.ExternalProduct, 0, len(s.ordered))
+	for _, p := range s.ordered {
+		if !s.isMissing(p.ID) {
+			out = append(out, p)
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	if !s.inject(w, r) {
+		return
+	}
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid product id"})
+		return
+	}
+	p, ok := s.products[uint(id)]
+	if !ok || s.isMissing(p.ID) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "product not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+// faultsBody é o formato de /_stub/faults, com a latência escrita como
+// duração do Go ("250ms").
+type faultsBody struct {
+	Latency    string `json:"latency"`
+	FailNext   int    `json:"fail_next"`
+	FailStatus int    `json:"fail_status"`
+	Missing    []uint `json:"missing"`
+}
+
+func (s *Server) getFaults(w http.ResponseWriter, _ *http.Request) {
+	f := s.Faults()
+	writeJSON(w, http.StatusOK, faultsBody{
+		Latency:    f.Latency.String(),
+		FailNext:   f.FailNext,
+		FailStatus: f.FailStatus,
+		Missing:    f.Missing,
+	})
+}
+
+func (s *Server) putFaults(w http.ResponseWriter, r *http.Request) {
+	var body faultsBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	var latency time.Duration
+	if body.Latency != "" {
+		d, err := time.ParseDuration(body.Latency)
+		if err != nil || d < 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "latency must be a non-negative duration"})
+			return
+		}
+		latency = d
+	}
+	if body.FailNext < 0 || (body.FailStatus != 0 && (body.FailStatus < 500 || body.FailStatus > 599)) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "fail_next must not be negative and fail_status must be a 5xx status"})
+		return
+	}
+	s.SetFaults(Faults{Latency: latency, FailNext: body.FailNext, FailStatus: body.FailStatus, Missing: body.Missing})
+	s.getFaults(w, r)
+}
+
+func (s *Server) stats(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]int64{"requests": s.Requests()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "ratelimit:"
+
+// Result descreve a decisão para uma requisição.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter é o tempo até uma nova requisição ser aceita; só é
+	// preenchido quando Allowed é falso.
+	RetryAfter time.Duration
+	// Reset é o tempo até a janela ficar totalmente livre.
+	Reset time.Duration
+}
+
+// slidingWindow mantém um sorted set por chave com o instante de cada
+// requisição aceita. Entradas mais antigas que a janela são descartadas antes
+// da contagem, e tudo acontece em um único script para que réplicas
+// concorrentes não ultrapassem o limite.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local member = ARGV[4]
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+  redis.call('ZADD', key, now, member)
+  count = count + 1
+  allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+local reset = 0
+if oldest[2] then
+  reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+// RedisLimiter aplica um limite de janela deslizante compartilhado entre
+// todas as réplicas do serviço.
+type RedisLimiter struct {
+	rdb *redis.Client
+}
+
+func NewRedisLimiter(rdb *redis.Client) *RedisLimiter {
+	return &RedisLimiter{rdb: rdb}
+}
+
+// Allow registra uma requisição para key e informa se ela cabe no limite de
+// requests por window.
+func (l *RedisLimiter) Allow(ctx context.Context, key string, requests int, window time.Duration) (Result, error) {
+	now := time.Now().UnixMilli()
+	res, err := slidingWindow.Run(ctx, l.rdb, []string{keyPrefix + key},
+		now, window.Milliseconds(), requests, member(now)).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed, count, reset := res[0] == 1, int(res[1]), time.Duration(res[2])*time.Millisecond
+	result := Result{
+		Allowed:   allowed,
+		Limit:     requests,
+		Remaining: max(requests-count, 0),
+		Reset:     reset,
+	}
+	if !allowed {
+		// A janela só libera espaço quando a requisição mais antiga sai dela.
+		result.RetryAfter = reset
+	}
+	return result, nil
+}
+
+// member torna únicas requisições feitas no mesmo milissegundo.
+func member(now int64) string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return strconv.FormatInt(now, 10) + "-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,427 @@
+// Package redistest sobe um servidor Redis em memória, suficiente para os
+// comandos usados pelo serviço. Deve ser importado apenas por testes.
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Server é um servidor RESP2 em memória com GET, SET (com EX, PX e NX),
+// SETNX, DEL, EXISTS, INCR, EXPIRE, HGET, HSET, WATCH, MULTI, EXEC e
+// DISCARD. Comandos desconhecidos (como HELLO) respondem com erro, como um
+// Redis antigo.
+type Server struct {
+	ln net.Listener
+
+	mutex    sync.Mutex
+	strs     map[string]string
+	hashes   map[string]map[string]string
+	expires  map[string]time.Time
+	versions map[string]uint64 // incrementada a cada escrita, para o WATCH
+	conns    map[net.Conn]struct{}
+}
+
+// NewServer sobe o servidor em uma porta local livre e o fecha ao fim do
+// teste.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		ln:       ln,
+		strs:     map[string]string{},
+		hashes:   map[string]map[string]string{},
+		expires:  map[string]time.Time{},
+		versions: map[string]uint64{},
+		conns:    map[net.Conn]struct{}{},
+	}
+	go s.serve()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Client devolve um cliente sem novas tentativas, para que falhas apareçam
+// de imediato. O cliente é fechado ao fim do teste.
+func (s *Server) Client(t testing.TB) *redis.Client {
+	t.Helper()
+	c := redis.NewClient(&redis.Options{
+		Addr:            s.ln.Addr().String(),
+		Protocol:        2,
+		DisableIdentity: true,
+		MaxRetries:      -1,
+		DialTimeout:     100 * time.Millisecond,
+	})
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// Close derruba o servidor e as conexões abertas, simulando o Redis fora.
+func (s *Server) Close() {
+	s.ln.Close()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for c := range s.conns {
+		c.Close()
+	}
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		s.conns[conn] = struct{}{}
+		s.mutex.Unlock()
+		go s.handle(conn)
+	}
+}
+
+// session é o estado de uma conexão: chaves observadas e transação aberta.
+type session struct {
+	watched map[string]uint64
+	multi   bool
+	queued  [][]string
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mutex.Lock()
+		delete(s.conns, conn)
+		s.mutex.Unlock()
+	}()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	sess := &session{}
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		s.dispatch(w, sess, args)
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) dispatch(w *bufio.Writer, sess *session, args []string) {
+	name := strings.ToUpper(args[0])
+	switch {
+	case name == "MULTI":
+		sess.multi, sess.queued = true, nil
+		writeSimple(w, "OK")
+	case name == "DISCARD":
+		sess.multi, sess.queued, sess.watched = false, nil, nil
+		writeSimple(w, "OK")
+	case name == "EXEC":
+		s.exec(w, sess)
+	case name == "WATCH":
+		s.mutex.Lock()
+		if sess.watched == nil {
+			sess.watched = map[string]uint64{}
+		}
+		for _, key := range args[1:] {
+			sess.watched[key] = s.versions[key]
+		}
+		s.mutex.Unlock()
+		writeSimple(w, "OK")
+	case name == "UNWATCH":
+		sess.watched = nil
+		writeSimple(w, "OK")
+	case sess.multi:
+		sess.queued = append(sess.queued, args)
+		writeSimple(w, "QUEUED")
+	default:
+		s.mutex.Lock()
+		reply := s.apply(args)
+		s.mutex.Unlock()
+		writeReply(w, reply)
+	}
+}
+
+func (s *Server) exec(w *bufio.Writer, sess *session) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	defer func() { sess.multi, sess.queued, sess.watched = false, nil, nil }()
+
+	for key, version := range sess.watched {
+		if s.versions[key] != version {
+			// Alguma chave observada mudou: a transação é descartada.
+			fmt.Fprint(w, "*-1\r\n")
+			return
+		}
+	}
+	replies := make([]interface{}, 0, len(sess.queued))
+	for _, args := range sess.queued {
+		replies = append(replies, s.apply(args))
+	}
+	writeReply(w, replies)
+}
+
+// apply executa um comando com o lock seguro e devolve a resposta: string
+// (bulk), int64, nil (bulk nulo), error, simpleString ou []interface{}.
+func (s *Server) apply(args []string) interface{} {
+	name := strings.ToUpper(args[0])
+	for _, key := range args[1:min(len(args), 2)] {
+		s.expireLocked(key)
+	}
+
+	switch name {
+	case "PING":
+		return simpleString("PONG")
+	case "GET":
+		if len(args) != 2 {
+			return errWrongArgs
+		}
+		if v, ok := s.strs[args[1]]; ok {
+			return v
+		}
+		return nil
+	case "SET":
+		if len(args) < 3 {
+			return errWrongArgs
+		}
+		var ttl time.Duration
+		nx := false
+		for i := 3; i < len(args); i++ {
+			switch opt := strings.ToUpper(args[i]); opt {
+			case "NX":
+				nx = true
+			case "EX", "PX":
+				if i+1 >= len(args) {
+					return errSyntax
+				}
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil || n <= 0 {
+					return errors.New("ERR invalid expire time in 'set' command")
+				}
+				unit := time.Second
+				if opt == "PX" {
+					unit = time.Millisecond
+				}
+				ttl = time.Duration(n) * unit
+				i++
+			default:
+				return errSyntax
+			}
+		}
+		if nx && s.existsLocked(args[1]) {
+			return nil
+		}
+		s.setLocked(args[1], args[2], ttl)
+		return simpleString("OK")
+	case "SETNX":
+		if len(args) != 3 {
+			return errWrongArgs
+		}
+		if s.existsLocked(args[1]) {
+			return int64(0)
+		}
+		s.setLocked(args[1], args[2], 0)
+		return int64(1)
+	case "EXISTS":
+		var n int64
+		for _, key := range args[1:] {
+			s.expireLocked(key)
+			if s.existsLocked(key) {
+				n++
+			}
+		}
+		return n
+	case "DEL":
+		var n int64
+		for _, key := range args[1:] {
+			s.expireLocked(key)
+			if s.deleteLocked(key) {
+				n++
+			}
+		}
+		return n
+	case "INCR":
+		if len(args) != 2 {
+			return errWrongArgs
+		}
+		n, err := strconv.ParseInt(s.strs[args[1]], 10, 64)
+		if _, ok := s.strs[args[1]]; ok && err != nil {
+			return errors.New("ERR value is not an integer or out of range")
+		}
+		n++
+		s.strs[args[1]] = strconv.FormatInt(n, 10)
+		s.touchLocked(args[1])
+		return n
+	case "EXPIRE":
+		if len(args) != 3 {
+			return errWrongArgs
+		}
+		secs, err := strconv.Atoi(args[2])
+		if err != nil {
+			return errors.New("ERR value is not an integer or out of range")
+		}
+		if !s.existsLocked(args[1]) {
+			return int64(0)
+		}
+		s.expires[args[1]] = time.Now().Add(time.Duration(secs) * time.Second)
+		return int64(1)
+	case "HGET":
+		if len(args) != 3 {
+			return errWrongArgs
+		}
+		if v, ok := s.hashes[args[1]][args[2]]; ok {
+			return v
+		}
+		return nil
+	case "HSET":
+		if len(args) < 4 || len(args)%2 != 0 {
+			return errWrongArgs
+		}
+		h := s.hashes[args[1]]
+		if h == nil {
+			h = map[string]string{}
+			s.hashes[args[1]] = h
+		}
+		var added int64
+		for i := 2; i < len(args); i += 2 {
+			if _, ok := h[args[i]]; !ok {
+				added++
+			}
+			h[args[i]] = args[i+1]
+		}
+		s.touchLocked(args[1])
+		return added
+	default:
+		return fmt.Errorf("ERR unknown command '%s'", strings.ToLower(name))
+	}
+}
+
+var (
+	errWrongArgs = errors.New("ERR wrong number of arguments")
+	errSyntax    = errors.New("ERR syntax error")
+)
+
+type simpleString string
+
+func (s *Server) existsLocked(key string) bool {
+	_, isString := s.strs[key]
+	_, isHash := s.hashes[key]
+	return isString || isHash
+}
+
+func (s *Server) setLocked(key, value string, ttl time.Duration) {
+	s.deleteLocked(key)
+	s.strs[key] = value
+	if ttl > 0 {
+		s.expires[key] = time.Now().Add(ttl)
+	}
+	s.touchLocked(key)
+}
+
+func (s *Server) deleteLocked(key string) bool {
+	existed := s.existsLocked(key)
+	delete(s.strs, key)
+	delete(s.hashes, key)
+	delete(s.expires, key)
+	if existed {
+		s.touchLocked(key)
+	}
+	return existed
+}
+
+func (s *Server) touchLocked(key string) {
+	s.versions[key]++
+}
+
+func (s *Server) expireLocked(key string) {
+	if at, ok := s.expires[key]; ok && !time.Now().Before(at) {
+		s.deleteLocked(key)
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		// Comando inline, como os enviados por redis-cli via telnet.
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return nil, errors.New("empty command")
+		}
+		return fields, nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("invalid array header %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(header) == 0 || header[0] != '$' {
+			return nil, fmt.Errorf("invalid bulk header %q", header)
+		}
+		size, err := strconv.Atoi(header[1:])
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("invalid bulk header %q", header)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func writeSimple(w *bufio.Writer, s string) {
+	fmt.Fprintf(w, "+%s\r\n", s)
+}
+
+func writeReply(w *bufio.Writer, v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		fmt.Fprint(w, "$-1\r\n")
+	case simpleString:
+		writeSimple(w, string(v))
+	case string:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case error:
+		fmt.Fprintf(w, "-%s\r\n", v.Error())
+	case []interface{}:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, item := range v {
+			writeReply(w, item)
+		}
+	default:
+		panic(fmt.Sprintf("redistest: unsupported reply %T", v))
+	}
+}
@@ -0,0 +1,65 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/authentication"
+)
+
+const (
+	refreshTokenKey  = "auth:refresh:"
+	refreshUsedKey   = "auth:refresh:used:"
+	refreshFamilyKey = "auth:refresh:family:"
+)
+
+type RefreshTokenStore struct {
+	rdb *redis.Client
+}
+
+func NewRefreshTokenStore(rdb *redis.Client) *RefreshTokenStore {
+	return &RefreshTokenStore{rdb: rdb}
+}
+
+func (s *RefreshTokenStore) Save(ctx context.Context, tokenHash string, session domain.RefreshSession) error {
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return s.rdb.Set(ctx, refreshTokenKey+tokenHash, payload, time.Until(session.ExpiresAt)).Err()
+}
+
+func (s *RefreshTokenStore) Find(ctx context.Context, tokenHash string) (domain.RefreshSession, error) {
+	payload, err := s.rdb.Get(ctx, refreshTokenKey+tokenHash).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return domain.RefreshSession{}, domain.ErrRefreshSessionNotFound
+	}
+	if err != nil {
+		return domain.RefreshSession{}, err
+	}
+
+	var session domain.RefreshSession
+	if err := json.Unmarshal(payload, &session); err != nil {
+		return domain.RefreshSession{}, err
+	}
+	return session, nil
+}
+
+// MarkUsed usa SETNX para que, entre duas rotações concorrentes do mesmo
+// token, apenas uma seja considerada legítima.
+func (s *RefreshTokenStore) MarkUsed(ctx context.Context, tokenHash string, until time.Time) (bool, error) {
+	return s.rdb.SetNX(ctx, refreshUsedKey+tokenHash, 1, time.Until(until)).Result()
+}
+
+func (s *RefreshTokenStore) RevokeFamily(ctx context.Context, familyID string, until time.Time) error {
+	return s.rdb.Set(ctx, refreshFamilyKey+familyID, "revoked", time.Until(until)).Err()
+}
+
+func (s *RefreshTokenStore) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	n, err := s.rdb.Exists(ctx, refreshFamilyKey+familyID).Result()
+	return n > 0, err
+}
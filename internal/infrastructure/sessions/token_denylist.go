@@ -0,0 +1,33 @@
+package sessions
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const denylistKey = "auth:denylist:"
+
+type TokenDenylist struct {
+	rdb *redis.Client
+}
+
+func NewTokenDenylist(rdb *redis.Client) *TokenDenylist {
+	return &TokenDenylist{rdb: rdb}
+}
+
+// Revoke mantém o jti na lista apenas até o token expirar; depois disso a
+// validação de exp já o rejeita.
+func (d *TokenDenylist) Revoke(ctx context.Context, jti string, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	return d.rdb.Set(ctx, denylistKey+jti, 1, ttl).Err()
+}
+
+func (d *TokenDenylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := d.rdb.Exists(ctx, denylistKey+jti).Result()
+	return n > 0, err
+}
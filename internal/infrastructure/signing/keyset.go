@@ -0,0 +1,218 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits é o menor tamanho de chave RSA aceito para assinar ou verificar.
+const minRSABits = 2048
+
+var (
+	ErrUnknownKeyID = errors.New("kid desconhecido")
+	ErrKeyMismatch  = errors.New("algoritmo do token não corresponde à chave")
+)
+
+type verificationKey struct {
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+// KeySet assina tokens com uma única chave ativa e verifica tokens assinados
+// por qualquer uma das chaves conhecidas, o que permite rotacionar chaves sem
+// invalidar os tokens já emitidos.
+type KeySet struct {
+	signingKID    string
+	signingMethod jwt.SigningMethod
+	signingKey    crypto.Signer
+	verification  map[string]verificationKey
+	order         []string
+}
+
+// NewKeySet cria o conjunto a partir da chave privada ativa e das chaves
+// públicas adicionais (indexadas por kid) ainda aceitas na verificação.
+func NewKeySet(kid string, private crypto.Signer, previous map[string]crypto.PublicKey) (*KeySet, error) {
+	if kid == "" {
+		return nil, errors.New("signing key id is required")
+	}
+	method, err := methodFor(private.Public())
+	if err != nil {
+		return nil, fmt.Errorf("signing key %s: %w", kid, err)
+	}
+
+	ks := &KeySet{
+		signingKID:    kid,
+		signingMethod: method,
+		signingKey:    private,
+		verification:  map[string]verificationKey{},
+	}
+	ks.add(kid, method, private.Public())
+
+	ids := make([]string, 0, len(previous))
+	for id := range previous {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		public := previous[id]
+		if _, exists := ks.verification[id]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", id)
+		}
+		method, err := methodFor(public)
+		if err != nil {
+			return nil, fmt.Errorf("verification key %s: %w", id, err)
+		}
+		ks.add(id, method, public)
+	}
+	return ks, nil
+}
+
+// GenerateEphemeral cria um KeySet Ed25519 em memória. Serve apenas para
+// desenvolvimento: os tokens deixam de valer quando o processo reinicia.
+func GenerateEphemeral() (*KeySet, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySet("ephemeral", private, nil)
+}
+
+// LoadKeySet lê a chave privada ativa de signingKeyFile e as chaves públicas
+// listadas em verificationKeys, no formato "kid=caminho.pem,kid2=outro.pem".
+func LoadKeySet(signingKID, signingKeyFile, verificationKeys string) (*KeySet, error) {
+	private, err := readPrivateKey(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := map[string]crypto.PublicKey{}
+	for _, entry := range strings.Split(verificationKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kid, path, found := strings.Cut(entry, "=")
+		if !found || kid == "" || path == "" {
+			return nil, fmt.Errorf("invalid verification key entry %q, expected kid=path", entry)
+		}
+		public, err := readPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		previous[kid] = public
+	}
+
+	return NewKeySet(signingKID, private, previous)
+}
+
+func (ks *KeySet) add(kid string, method jwt.SigningMethod, public crypto.PublicKey) {
+	ks.verification[kid] = verificationKey{method: method, public: public}
+	ks.order = append(ks.order, kid)
+}
+
+// Sign assina as claims com a chave ativa, gravando o kid no cabeçalho.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signingMethod, claims)
+	token.Header["kid"] = ks.signingKID
+	return token.SignedString(ks.signingKey)
+}
+
+// Methods devolve os algoritmos aceitos na verificação.
+func (ks *KeySet) Methods() []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, kid := range ks.order {
+		alg := ks.verification[kid].method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			out = append(out, alg)
+		}
+	}
+	return out
+}
+
+// Keyfunc escolhe a chave de verificação pelo kid do token e garante que o
+// algoritmo declarado no token é o da chave.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.verification[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, ErrKeyMismatch
+	}
+	return key.public, nil
+}
+
+func methodFor(public crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA keys must have at least %d bits", minRSABits)
+		}
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", public)
+	}
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key %s: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s is not PEM encoded", path)
+	}
+	return block, nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("key %s cannot be used for signing", path)
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("key %s is not a PKCS#8 or PKCS#1 private key", path)
+}
+
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("key %s is not a PKIX or PKCS#1 public key", path)
+}
@@ -0,0 +1,61 @@
+package http_interfaces_alert
+
+import (
+	"context"
+
+	usecase "github.com/vinihss/aiqfome/internal/usecases/alert"
+)
+
+type AlertController struct {
+	rules *usecase.RuleUseCase
+	feed  *usecase.FeedUseCase
+}
+
+func NewAlertController(rules *usecase.RuleUseCase, feed *usecase.FeedUseCase) *AlertController {
+	return &AlertController{rules: rules, feed: feed}
+}
+
+func (ctrl *AlertController) SetRule(ctx context.Context, customerID, productID uint, req SetRuleRequest) (RuleResponse, error) {
+	rule, err := ctrl.rules.Set(ctx, usecase.SetRuleInput{
+		CustomerID: customerID,
+		ProductID:  productID,
+		Kind:       req.Kind,
+		Threshold:  req.Threshold,
+	})
+	if err != nil {
+		return RuleResponse{}, err
+	}
+	return ToRuleResponse(rule), nil
+}
+
+func (ctrl *AlertController) GetRule(ctx context.Context, customerID, productID uint) (RuleResponse, error) {
+	rule, err := ctrl.rules.Get(ctx, customerID, productID)
+	if err != nil {
+		return RuleResponse{}, err
+	}
+	return ToRuleResponse(rule), nil
+}
+
+func (ctrl *AlertController) DeleteRule(ctx context.Context, customerID, productID uint) error {
+	return ctrl.rules.Delete(ctx, customerID, productID)
+}
+
+func (ctrl *AlertController) ListAlerts(ctx context.Context, customerID uint, unacknowledgedOnly bool, limit int) ([]AlertResponse, error) {
+	alerts, err := ctrl.feed.List(ctx, customerID, unacknowledgedOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]AlertResponse, 0, len(alerts))
+	for _, a := range alerts {
+		out = append(out, ToAlertResponse(a))
+	}
+	return out, nil
+}
+
+func (ctrl *AlertController) Acknowledge(ctx context.Context, customerID, alertID uint) (AlertResponse, error) {
+	a, err := ctrl.feed.Acknowledge(ctx, customerID, alertID)
+	if err != nil {
+		return AlertResponse{}, err
+	}
+	return ToAlertResponse(a), nil
+}
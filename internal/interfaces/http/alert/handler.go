@@ -0,0 +1,222 @@
+package http_interfaces_alert
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	usecase "github.com/vinihss/aiqfome/internal/usecases/alert"
+)
+
+const (
+	defaultAlertsLimit = 50
+	maxAlertsLimit     = 200
+)
+
+type AlertHandler struct {
+	controller *AlertController
+}
+
+func NewAlertHandler(controller *AlertController) *AlertHandler {
+	return &AlertHandler{controller: controller}
+}
+
+// SetRule godoc
+// @Summary Definir alerta de preço de um favorito
+// @Description Cria ou substitui a regra de alerta do favorito. any_drop avisa a cada queda; below_price quando o preço fica abaixo de threshold; drop_percent quando cai threshold% em relação ao preço atual. Cada cruzamento gera um único alerta.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Param productId path int true "Product ID"
+// @Param body body SetRuleRequest true "Regra"
+// @Success 200 {object} RuleResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customer/{id}/favorites/{productId}/alert [put]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *AlertHandler) SetRule(c *gin.Context) {
+	customerID, productID, ok := favoriteParams(c)
+	if !ok {
+		return
+	}
+	var req SetRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.controller.SetRule(c.Request.Context(), customerID, productID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidRule):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "threshold deve ser um preço positivo em below_price e estar entre 0 e 100 em drop_percent"})
+		case errors.Is(err, usecase.ErrFavoriteNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetRule godoc
+// @Summary Consultar alerta de preço de um favorito
+// @Tags Alerts
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Param productId path int true "Product ID"
+// @Success 200 {object} RuleResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customer/{id}/favorites/{productId}/alert [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *AlertHandler) GetRule(c *gin.Context) {
+	customerID, productID, ok := favoriteParams(c)
+	if !ok {
+		return
+	}
+	res, err := h.controller.GetRule(c.Request.Context(), customerID, productID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// DeleteRule godoc
+// @Summary Remover alerta de preço de um favorito
+// @Tags Alerts
+// @Param id path int true "Customer ID"
+// @Param productId path int true "Product ID"
+// @Success 204 {object} nil
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customer/{id}/favorites/{productId}/alert [delete]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *AlertHandler) DeleteRule(c *gin.Context) {
+	customerID, productID, ok := favoriteParams(c)
+	if !ok {
+		return
+	}
+	if err := h.controller.DeleteRule(c.Request.Context(), customerID, productID); err != nil {
+		if errors.Is(err, usecase.ErrRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// List godoc
+// @Summary Listar alertas de preço do cliente
+// @Description Alertas emitidos, do mais recente para o mais antigo.
+// @Tags Alerts
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Param unacknowledged query bool false "Apenas alertas ainda não reconhecidos"
+// @Param limit query int false "Máximo de alertas (1-200)" default(50)
+// @Success 200 {array} AlertResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customer/{id}/alerts [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *AlertHandler) List(c *gin.Context) {
+	customerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || customerID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		return
+	}
+	unacknowledged, err := strconv.ParseBool(c.DefaultQuery("unacknowledged", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unacknowledged must be true or false"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultAlertsLimit)))
+	if err != nil || limit < 1 || limit > maxAlertsLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxAlertsLimit)})
+		return
+	}
+
+	res, err := h.controller.ListAlerts(c.Request.Context(), uint(customerID), unacknowledged, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Acknowledge godoc
+// @Summary Reconhecer alerta de preço
+// @Description Marca o alerta como visto. Reconhecer de novo mantém a data original.
+// @Tags Alerts
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Param alertId path int true "Alert ID"
+// @Success 200 {object} AlertResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customer/{id}/alerts/{alertId}/ack [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *AlertHandler) Acknowledge(c *gin.Context) {
+	customerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || customerID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		return
+	}
+	alertID, err := strconv.Atoi(c.Param("alertId"))
+	if err != nil || alertID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert ID"})
+		return
+	}
+
+	res, err := h.controller.Acknowledge(c.Request.Context(), uint(customerID), uint(alertID))
+	if err != nil {
+		if errors.Is(err, usecase.ErrAlertNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// favoriteParams lê os parâmetros :id e :productId, respondendo 400 se algum
+// for inválido.
+func favoriteParams(c *gin.Context) (customerID, productID uint, ok bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		return 0, 0, false
+	}
+	pid, err := strconv.Atoi(c.Param("productId"))
+	if err != nil || pid <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return 0, 0, false
+	}
+	return uint(id), uint(pid), true
+}
@@ -0,0 +1,9 @@
+package http_interfaces_alert
+
+type SetRuleRequest struct {
+	// Kind é any_drop, below_price ou drop_percent.
+	Kind string `json:"kind" binding:"required,oneof=any_drop below_price drop_percent"`
+	// Threshold é o preço para below_price e a queda percentual (entre 0 e
+	// 100) para drop_percent.
+	Threshold float32 `json:"threshold"`
+}
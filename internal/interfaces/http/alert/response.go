@@ -0,0 +1,61 @@
+package http_interfaces_alert
+
+import (
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/alert"
+)
+
+type RuleResponse struct {
+	ID             uint      `json:"id"`
+	CustomerID     uint      `json:"customer_id"`
+	ProductID      uint      `json:"product_id"`
+	Kind           string    `json:"kind"`
+	Threshold      float32   `json:"threshold,omitempty"`
+	ReferencePrice float32   `json:"reference_price"`
+	LastPrice      float32   `json:"last_price"`
+	Triggered      bool      `json:"triggered"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func ToRuleResponse(r domain.Rule) RuleResponse {
+	return RuleResponse{
+		ID:             r.ID,
+		CustomerID:     r.CustomerID,
+		ProductID:      r.ProductID,
+		Kind:           r.Kind,
+		Threshold:      r.Threshold,
+		ReferencePrice: r.ReferencePrice,
+		LastPrice:      r.LastPrice,
+		Triggered:      r.Triggered,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
+
+type AlertResponse struct {
+	ID             uint       `json:"id"`
+	CustomerID     uint       `json:"customer_id"`
+	ProductID      uint       `json:"product_id"`
+	Kind           string     `json:"kind"`
+	Threshold      float32    `json:"threshold,omitempty"`
+	OldPrice       float32    `json:"old_price"`
+	NewPrice       float32    `json:"new_price"`
+	CreatedAt      time.Time  `json:"created_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+func ToAlertResponse(a domain.Alert) AlertResponse {
+	return AlertResponse{
+		ID:             a.ID,
+		CustomerID:     a.CustomerID,
+		ProductID:      a.ProductID,
+		Kind:           a.Kind,
+		Threshold:      a.Threshold,
+		OldPrice:       a.OldPrice,
+		NewPrice:       a.NewPrice,
+		CreatedAt:      a.CreatedAt,
+		AcknowledgedAt: a.AcknowledgedAt,
+	}
+}
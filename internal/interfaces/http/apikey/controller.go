@@ -0,0 +1,45 @@
+package http_interfaces_apikey
+
+import (
+	authdomain "github.com/vinihss/aiqfome/internal/domain/authentication"
+	"github.com/vinihss/aiqfome/internal/usecases/authentication"
+)
+
+type APIKeyController struct {
+	useCase *authentication.APIKeyUseCase
+}
+
+func NewAPIKeyController(useCase *authentication.APIKeyUseCase) *APIKeyController {
+	return &APIKeyController{useCase: useCase}
+}
+
+func (ctrl *APIKeyController) CreateAPIKey(creator authdomain.Principal, req CreateAPIKeyRequest) (CreateAPIKeyResponse, error) {
+	key, plain, err := ctrl.useCase.Create(authentication.CreateAPIKeyInput{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+		Creator:   creator,
+	})
+	if err != nil {
+		return CreateAPIKeyResponse{}, err
+	}
+
+	return CreateAPIKeyResponse{APIKeyResponse: ToAPIKeyResponse(key), Key: plain}, nil
+}
+
+func (ctrl *APIKeyController) ListAPIKeys() ([]APIKeyResponse, error) {
+	keys, err := ctrl.useCase.List()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, ToAPIKeyResponse(k))
+	}
+	return out, nil
+}
+
+func (ctrl *APIKeyController) RevokeAPIKey(id uint) error {
+	return ctrl.useCase.Revoke(id)
+}
@@ -0,0 +1,118 @@
+package http_interfaces_apikey
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vinihss/aiqfome/internal/usecases/authentication"
+	"github.com/vinihss/aiqfome/middlewares"
+)
+
+type APIKeyHandler struct {
+	controller *APIKeyController
+}
+
+func NewAPIKeyHandler(controller *APIKeyController) *APIKeyHandler {
+	return &APIKeyHandler{controller: controller}
+}
+
+// Create godoc
+// @Summary Criar API key
+// @Description Cria uma API key para sistemas internos. O valor da chave só é devolvido nesta resposta. Quem não é administrador só concede escopos que já tem.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param body body CreateAPIKeyRequest true "Dados da chave"
+// @Success 201 {object} CreateAPIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/api-keys [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	principal, ok := middlewares.PrincipalFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token required"})
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.controller.CreateAPIKey(principal, req)
+	if err != nil {
+		if errors.Is(err, authentication.ErrScopeNotGranted) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, authentication.ErrInvalidScope) || errors.Is(err, authentication.ErrInvalidExpiry) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// List godoc
+// @Summary Listar API keys
+// @Tags API Keys
+// @Produce json
+// @Success 200 {array} APIKeyResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/api-keys [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *APIKeyHandler) List(c *gin.Context) {
+	res, err := h.controller.ListAPIKeys()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Revoke godoc
+// @Summary Revogar API key
+// @Tags API Keys
+// @Param id path int true "API key ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/api-keys/{id} [delete]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := h.controller.RevokeAPIKey(uint(id)); err != nil {
+		if errors.Is(err, authentication.ErrAPIKeyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,33 +1,135 @@
 package http_interfaces_authentication
 
 import (
+	"context"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/vinihss/aiqfome/config"
+	domain "github.com/vinihss/aiqfome/internal/domain/authentication"
+	"github.com/vinihss/aiqfome/internal/infrastructure/signing"
+	"github.com/vinihss/aiqfome/internal/usecases/authentication"
+	"github.com/vinihss/aiqfome/middlewares"
+	"strconv"
 	"time"
 )
 
 type AuthenticationController struct {
+	authenticateUC *authentication.AuthenticateUseCase
+	registerUC     *authentication.RegisterUseCase
+	refreshUC      *authentication.RefreshUseCase
+	logoutUC       *authentication.LogoutUseCase
+	keys           *signing.KeySet
+	jwt            config.JWTConfig
 }
 
-func NewAuthenticationController() *AuthenticationController {
-	return &AuthenticationController{}
+func NewAuthenticationController(
+	authenticateUC *authentication.AuthenticateUseCase,
+	registerUC *authentication.RegisterUseCase,
+	refreshUC *authentication.RefreshUseCase,
+	logoutUC *authentication.LogoutUseCase,
+	keys *signing.KeySet,
+	cfg config.JWTConfig,
+) *AuthenticationController {
+	return &AuthenticationController{
+		authenticateUC: authenticateUC,
+		registerUC:     registerUC,
+		refreshUC:      refreshUC,
+		logoutUC:       logoutUC,
+		keys:           keys,
+		jwt:            cfg,
+	}
 }
 
-func (ctrl *AuthenticationController) CreateAuthentication(req CreateAuthenticationRequest) (AuthenticationResponse, error) {
+func (ctrl *AuthenticationController) CreateAuthentication(ctx context.Context, req CreateAuthenticationRequest) (AuthenticationResponse, error) {
+	identity, err := ctrl.authenticateUC.Execute(req.Email, req.Password)
+	if err != nil {
+		return AuthenticationResponse{}, err
+	}
 
-	token, _ := createToken(req.Name)
-	return AuthenticationResponse{
-		Token: token,
+	refreshToken, err := ctrl.refreshUC.Issue(ctx, identity)
+	if err != nil {
+		return AuthenticationResponse{}, err
+	}
+	return ctrl.newAuthenticationResponse(identity, refreshToken)
+}
+
+func (ctrl *AuthenticationController) Register(ctx context.Context, req RegisterRequest) (RegisterResponse, error) {
+	c, identity, err := ctrl.registerUC.Execute(authentication.RegisterInput{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		return RegisterResponse{}, err
+	}
+
+	refreshToken, err := ctrl.refreshUC.Issue(ctx, identity)
+	if err != nil {
+		return RegisterResponse{}, err
+	}
+	auth, err := ctrl.newAuthenticationResponse(identity, refreshToken)
+	if err != nil {
+		return RegisterResponse{}, err
+	}
+	return RegisterResponse{
+		ID:                     c.ID,
+		Name:                   c.Name,
+		Email:                  c.Email,
+		AuthenticationResponse: auth,
 	}, nil
 }
-func createToken(username string) (string, error) {
-	claims := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": username,                         // Subject (user identifier)
-		"iss": "aiqfome",                        // Issue
-		"exp": time.Now().Add(time.Hour).Unix(), // Expiration time
-		"iat": time.Now().Unix(),                // Issued at
+
+func (ctrl *AuthenticationController) Refresh(ctx context.Context, req RefreshRequest) (AuthenticationResponse, error) {
+	identity, refreshToken, err := ctrl.refreshUC.Execute(ctx, req.RefreshToken)
+	if err != nil {
+		return AuthenticationResponse{}, err
+	}
+	return ctrl.newAuthenticationResponse(identity, refreshToken)
+}
+
+func (ctrl *AuthenticationController) Logout(ctx context.Context, claims *middlewares.Claims, req LogoutRequest) error {
+	customerID, err := claims.CustomerID()
+	if err != nil {
+		return err
+	}
+	return ctrl.logoutUC.Execute(ctx, authentication.LogoutInput{
+		CustomerID:   customerID,
+		TokenID:      claims.ID,
+		ExpiresAt:    claims.ExpiresAt.Time,
+		RefreshToken: req.RefreshToken,
 	})
+}
+
+func (ctrl *AuthenticationController) newAuthenticationResponse(identity domain.Identity, refreshToken string) (AuthenticationResponse, error) {
+	token, err := ctrl.createToken(identity)
+	if err != nil {
+		return AuthenticationResponse{}, err
+	}
+	return AuthenticationResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(ctrl.jwt.TTL.Seconds()),
+	}, nil
+}
+
+func (ctrl *AuthenticationController) JWKS() signing.JWKSet {
+	return ctrl.keys.JWKS()
+}
+
+func (ctrl *AuthenticationController) createToken(identity domain.Identity) (string, error) {
+	now := time.Now()
+	claims := middlewares.Claims{
+		Roles: identity.Roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        authentication.NewTokenID(),                         // JWT ID, usado na revogação
+			Subject:   strconv.FormatUint(uint64(identity.CustomerID), 10), // Subject (customer ID)
+			Issuer:    ctrl.jwt.Issuer,                                     // Issue
+			Audience:  jwt.ClaimStrings{ctrl.jwt.Audience},                 // Audience
+			ExpiresAt: jwt.NewNumericDate(now.Add(ctrl.jwt.TTL)),           // Expiration time
+			IssuedAt:  jwt.NewNumericDate(now),                             // Issued at
+		},
+	}
 
-	tokenString, err := claims.SignedString([]byte("secreto123"))
+	tokenString, err := ctrl.keys.Sign(claims)
 	if err != nil {
 		return "", err
 	}
@@ -1,9 +1,14 @@
 package http_interfaces_authentication
 
 import (
-	"fmt"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/vinihss/aiqfome/internal/usecases/authentication"
+	"github.com/vinihss/aiqfome/middlewares"
 	"net/http"
+	"strings"
+
+	sharederrors "github.com/vinihss/aiqfome/internal/shared/errors"
 )
 
 type AuthenticationHandler struct {
@@ -18,24 +23,150 @@ func NewAuthenticationHandler(c *AuthenticationController) *AuthenticationHandle
 
 // Authenticate godoc
 // @Summary Create authentication token
-// @Description Creates a JWT token for the user
+// @Description Verifies the customer's email and password and returns a JWT whose subject is the customer ID
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param auth body CreateAuthenticationRequest true "Authentication data"
 // @Success 200 {object} AuthenticationResponse
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
 // @Router /authenticate [post]
-// @Security BearerAuth
 func (h *AuthenticationHandler) Authenticate(c *gin.Context) {
+	var req CreateAuthenticationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, sharederrors.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	res, err := h.controller.CreateAuthentication(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, authentication.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, sharederrors.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, sharederrors.ErrorResponse{Error: "failed to create token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Register godoc
+// @Summary Register customer
+// @Description Creates a customer together with its password credential and returns a JWT
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param customer body RegisterRequest true "Customer data"
+// @Success 201 {object} RegisterResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /register [post]
+func (h *AuthenticationHandler) Register(c *gin.Context) {
+	var req RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, sharederrors.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	res, err := h.controller.Register(c.Request.Context(), req)
+	if err != nil {
+		if isUniqueEmailErr(err) {
+			c.JSON(http.StatusConflict, sharederrors.ErrorResponse{Error: "email already registered"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, sharederrors.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
 
-	tokenString, err := createToken("test")
+// Refresh godoc
+// @Summary Refresh authentication token
+// @Description Exchanges a refresh token for a new access token and a new refresh token. Reusing a refresh token revokes the whole session
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param body body RefreshRequest true "Refresh token"
+// @Success 200 {object} AuthenticationResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /authenticate/refresh [post]
+func (h *AuthenticationHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, sharederrors.ErrorResponse{Error: err.Error()})
+		return
+	}
 
+	res, err := h.controller.Refresh(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Errorf("failed to create token: %w", err).Error()})
+		if errors.Is(err, authentication.ErrInvalidRefreshToken) || errors.Is(err, authentication.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, sharederrors.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, sharederrors.ErrorResponse{Error: "failed to refresh token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revokes the current access token and, when informed, the refresh token of the same session
+// @Tags Authentication
+// @Accept json
+// @Param body body LogoutRequest false "Refresh token to revoke"
+// @Success 204 {object} nil
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /logout [post]
+// @Security BearerAuth
+func (h *AuthenticationHandler) Logout(c *gin.Context) {
+	claims, ok := middlewares.ClaimsFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, sharederrors.ErrorResponse{Error: "Token required"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": tokenString})
+	var req LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, sharederrors.ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	if err := h.controller.Logout(c.Request.Context(), claims, req); err != nil {
+		c.JSON(http.StatusInternalServerError, sharederrors.ErrorResponse{Error: "failed to logout"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// JWKS godoc
+// @Summary JSON Web Key Set
+// @Description Public keys that partner services use to verify the tokens issued by this API
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} signing.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *AuthenticationHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.controller.JWKS())
+}
+
+func isUniqueEmailErr(err error) bool {
+	msg := strings.ToLower(err.Error())
+
+	return (strings.Contains(msg, "unique") || strings.Contains(msg, "duplicate") || strings.Contains(msg, "already exists")) &&
+		strings.Contains(msg, "email")
 }
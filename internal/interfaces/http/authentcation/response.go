@@ -1,5 +1,14 @@
 package http_interfaces_authentication
 
 type AuthenticationResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // segundos até o access token expirar
+}
+
+type RegisterResponse struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	AuthenticationResponse
 }
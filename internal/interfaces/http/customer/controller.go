@@ -1,6 +1,7 @@
 package http_interfaces_customer
 
 import (
+	domain "github.com/vinihss/aiqfome/internal/domain/customer"
 	"github.com/vinihss/aiqfome/internal/usecases/customer"
 )
 
@@ -75,20 +76,6 @@ func (ctrl *CustomerController) UpdateCustomer(id uint, req UpdateCustomerReques
 	}, nil
 }
 
-func (ctrl *CustomerController) GetAllCustomers(page, size int) ([]CustomerResponse, error) {
-	customers, err := ctrl.findUC.ExecuteAll(page, size)
-	if err != nil {
-		return nil, err
-	}
-
-	var responses []CustomerResponse
-	for _, c := range customers {
-		responses = append(responses, CustomerResponse{
-			ID:    c.ID,
-			Name:  c.Name,
-			Email: c.Email,
-		})
-	}
-
-	return responses, nil
+func (ctrl *CustomerController) GetAllCustomers(q domain.ListQuery) (domain.Page, error) {
+	return ctrl.findUC.ExecuteAll(q)
 }
@@ -1,10 +1,14 @@
 package http_interfaces_customer
 
 import (
-	"github.com/gin-gonic/gin"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/customer"
 )
 
 type CustomerHandler struct {
@@ -24,9 +28,11 @@ func NewCustomerHandler(controller *CustomerController) *CustomerHandler {
 // @Param favorite body CreateCustomerRequest true "Customer data"
 // @Success 200 {object} CustomerResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /customer [post]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *CustomerHandler) Create(c *gin.Context) {
 	var req CreateCustomerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -56,9 +62,11 @@ func (h *CustomerHandler) Create(c *gin.Context) {
 // @Param id path int true "Customer ID"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /customer/{id} [delete]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *CustomerHandler) Delete(c *gin.Context) {
 
 	id, err := strconv.Atoi(c.Param("id"))
@@ -85,9 +93,11 @@ func (h *CustomerHandler) Delete(c *gin.Context) {
 // @Param customer body UpdateCustomerRequest true "Updated customer data"
 // @Success 200 {object} CustomerResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /customer/{id} [put]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *CustomerHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -123,10 +133,12 @@ func (h *CustomerHandler) Update(c *gin.Context) {
 // @Param id path int true "Customer ID"
 // @Success 200 {object} CustomerResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /customer/{id} [get]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *CustomerHandler) FindByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -145,28 +157,43 @@ func (h *CustomerHandler) FindByID(c *gin.Context) {
 
 // GetAllCustomers godoc
 // @Summary Get all customers
-// @Description Retrieves a paginated list of customers
+// @Description Retrieves a paginated list of customers. links.next continues by cursor, which keeps deep pages cheap; page jumps to any page by number.
 // @Tags Customer
 // @Accept json
 // @Produce json
-// @Param page query int false "Page number"
-// @Param size query int false "Page size"
-// @Success 200 {array} CustomerResponse
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size (1-100)" default(20)
+// @Param cursor query string false "Opaque cursor taken from links.next; only valid with the same sort, order, q and email"
+// @Param sort query string false "Sort field" Enums(id, name, email) default(id)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(asc)
+// @Param q query string false "Name or email prefix, case-insensitive"
+// @Param email query string false "Exact email, case-insensitive"
+// @Success 200 {object} CustomersPageResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /customer [get]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *CustomerHandler) GetAllCustomers(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
+	var req ListCustomersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	res, err := h.controller.GetAllCustomers(page, size)
+	q := req.ToQuery().WithDefaults()
+	page, err := h.controller.GetAllCustomers(q)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidQuery) || errors.Is(err, domain.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, res)
+	c.JSON(http.StatusOK, ToCustomersPageResponse(c.Request.URL, q, page))
 }
 
 func isUniqueEmailErr(err error) bool {
@@ -1,5 +1,11 @@
 package http_interfaces_customer
 
+import (
+	"strings"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/customer"
+)
+
 type CreateCustomerRequest struct {
 	Name  string `json:"name"  binding:"required,min=2,max=100"`
 	Email string `json:"email" binding:"required,email,max=254"`
@@ -9,3 +15,26 @@ type UpdateCustomerRequest struct {
 	Name  string `json:"name"  binding:"omitempty,min=2,max=100"`
 	Email string `json:"email" binding:"omitempty,email,max=254"`
 }
+
+// ListCustomersRequest são os parâmetros de query da listagem de clientes.
+type ListCustomersRequest struct {
+	Page   int    `form:"page" binding:"omitempty,min=1,max=1000000"`
+	Size   int    `form:"size" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor" binding:"max=512"`
+	Sort   string `form:"sort" binding:"omitempty,oneof=id name email"`
+	Order  string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Q      string `form:"q" binding:"max=100"`
+	Email  string `form:"email" binding:"omitempty,email,max=254"`
+}
+
+func (r ListCustomersRequest) ToQuery() domain.ListQuery {
+	return domain.ListQuery{
+		Page:   r.Page,
+		Size:   r.Size,
+		Cursor: r.Cursor,
+		Sort:   r.Sort,
+		Desc:   r.Order == "desc",
+		Search: strings.TrimSpace(r.Q),
+		Email:  strings.TrimSpace(r.Email),
+	}
+}
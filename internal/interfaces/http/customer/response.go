@@ -1,7 +1,71 @@
 package http_interfaces_customer
 
+import (
+	"net/url"
+	"strconv"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/customer"
+)
+
 type CustomerResponse struct {
 	ID    uint   `json:"id"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
 }
+
+// PageLinks são os links de navegação da listagem. Next segue por cursor;
+// First, Prev e Last apontam para páginas por número.
+type PageLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last"`
+}
+
+type CustomersPageResponse struct {
+	Items []CustomerResponse `json:"items"`
+	Page  int                `json:"page"`
+	Size  int                `json:"size"`
+	Total int64              `json:"total"`
+	Links PageLinks          `json:"links"`
+}
+
+// ToCustomersPageResponse monta a resposta a partir da URL requisitada,
+// preservando filtros e ordenação nos links. q deve ter os valores padrão
+// já aplicados.
+func ToCustomersPageResponse(self *url.URL, q domain.ListQuery, page domain.Page) CustomersPageResponse {
+	items := make([]CustomerResponse, 0, len(page.Items))
+	for _, c := range page.Items {
+		items = append(items, CustomerResponse{ID: c.ID, Name: c.Name, Email: c.Email})
+	}
+
+	lastPage := 1
+	if page.Total > 0 {
+		lastPage = int((page.Total + int64(q.Size) - 1) / int64(q.Size))
+	}
+	link := func(n int, cursor string) string {
+		values := self.Query()
+		values.Set("page", strconv.Itoa(n))
+		values.Set("size", strconv.Itoa(q.Size))
+		values.Del("cursor")
+		if cursor != "" {
+			values.Set("cursor", cursor)
+		}
+		return self.Path + "?" + values.Encode()
+	}
+
+	links := PageLinks{
+		Self:  link(q.Page, q.Cursor),
+		First: link(1, ""),
+		Last:  link(lastPage, ""),
+	}
+	if q.Page > 1 {
+		links.Prev = link(q.Page-1, "")
+	}
+	if page.NextCursor != "" {
+		links.Next = link(q.Page+1, page.NextCursor)
+	}
+
+	return CustomersPageResponse{Items: items, Page: q.Page, Size: q.Size, Total: page.Total, Links: links}
+}
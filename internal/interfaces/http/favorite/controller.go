@@ -11,23 +11,29 @@ type FavoriteController struct {
 	addUC    *usecase.AddFavoriteUseCase
 	listUC   *usecase.ListFavoritesUseCase
 	removeUC *usecase.RemoveFavoriteUseCase
+	pricesUC *usecase.PriceHistoryUseCase
 }
 
 func NewFavoriteController(
 	add *usecase.AddFavoriteUseCase,
 	list *usecase.ListFavoritesUseCase,
-	remove *usecase.RemoveFavoriteUseCase) *FavoriteController {
-	return &FavoriteController{addUC: add, listUC: list, removeUC: remove}
+	remove *usecase.RemoveFavoriteUseCase,
+	prices *usecase.PriceHistoryUseCase) *FavoriteController {
+	return &FavoriteController{addUC: add, listUC: list, removeUC: remove, pricesUC: prices}
 }
 
 func (c *FavoriteController) Add(ctx context.Context, customerID, productID uint) (domain.Favorite, error) {
 	return c.addUC.Execute(ctx, customerID, productID)
 }
 
-func (c *FavoriteController) List(ctx context.Context, customerID uint) ([]domain.Favorite, error) {
-	return c.listUC.Execute(ctx, customerID)
+func (c *FavoriteController) List(ctx context.Context, q domain.ListQuery) (domain.Page, error) {
+	return c.listUC.Execute(ctx, q)
 }
 
 func (c *FavoriteController) Remove(ctx context.Context, customerID, productID uint) error {
 	return c.removeUC.Execute(ctx, customerID, productID)
 }
+
+func (c *FavoriteController) PriceHistory(ctx context.Context, customerID, productID uint, limit int) (usecase.PriceHistory, error) {
+	return c.pricesUC.Execute(ctx, customerID, productID, limit)
+}
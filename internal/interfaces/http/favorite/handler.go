@@ -7,12 +7,17 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 
-	"github.com/vinihss/aiqfome/internal/infrastructure/external_epis"
+	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
+	productdomain "github.com/vinihss/aiqfome/internal/domain/product"
 	usecase "github.com/vinihss/aiqfome/internal/usecases/favorite"
 )
 
+const (
+	defaultPriceHistoryLimit = 100
+	maxPriceHistoryLimit     = 500
+)
+
 type FavoriteHandler struct {
 	controller *FavoriteController
 }
@@ -30,11 +35,15 @@ func NewFavoriteHandler(controller *FavoriteController) *FavoriteHandler {
 // @Param body body AddFavoriteRequest true "Produto favorito"
 // @Success 201 {object} FavoriteResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /customer/{id}/favorites [post]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *FavoriteHandler) Create(c *gin.Context) {
 	customerID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || customerID <= 0 {
@@ -53,10 +62,15 @@ func (h *FavoriteHandler) Create(c *gin.Context) {
 			c.JSON(http.StatusConflict, gin.H{"error": "produto já favoritado para este cliente"})
 			return
 		}
-		if err == external_epis.ErrProductNotFound {
+		if errors.Is(err, productdomain.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "produto não encontrado"})
 			return
 		}
+		if errors.Is(err, productdomain.ErrUnavailable) {
+			c.Header("Retry-After", "60")
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "serviço de produtos temporariamente indisponível"})
+			return
+		}
 		msg := strings.ToLower(err.Error())
 		if strings.Contains(msg, "unique") || strings.Contains(msg, "duplicate") {
 			c.JSON(http.StatusConflict, gin.H{"error": "produto já favoritado para este cliente"})
@@ -65,36 +79,97 @@ func (h *FavoriteHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusCreated, ToFavoriteResponse(fav.ID, fav.CustomerID, fav.ProductID, fav.Title, fav.ImageUrl, fav.Price))
+	c.JSON(http.StatusCreated, ToFavoriteResponse(fav))
 }
 
 // List godoc
 // @Summary Listar favoritos do cliente
+// @Description Paginação por cursor: repita a chamada com o next_cursor da resposta, mantendo sort e order, até ele vir vazio.
 // @Tags Favorites
 // @Accept json
 // @Produce json
 // @Param id path int true "Customer ID"
-// @Success 200 {array} FavoriteResponse
+// @Param limit query int false "Itens por página (1-100)" default(20)
+// @Param cursor query string false "Cursor opaco devolvido em next_cursor; só vale com a mesma ordenação e os mesmos filtros"
+// @Param sort query string false "Ordenação" Enums(created_at, price, title) default(created_at)
+// @Param order query string false "Direção; padrão desc para created_at e asc para as demais" Enums(asc, desc)
+// @Param min_price query number false "Preço mínimo"
+// @Param max_price query number false "Preço máximo"
+// @Param q query string false "Busca no título"
+// @Param category query string false "Categoria do produto"
+// @Success 200 {object} FavoritesPageResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /customer/{id}/favorites [get]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *FavoriteHandler) List(c *gin.Context) {
 	customerID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || customerID <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
 		return
 	}
-	items, err := h.controller.List(c.Request.Context(), uint(customerID))
+	var req ListFavoritesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := h.controller.List(c.Request.Context(), req.ToQuery(uint(customerID)))
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) || errors.Is(err, domain.ErrInvalidQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	out := make([]FavoriteResponse, 0, len(items))
-	for _, it := range items {
-		out = append(out, ToFavoriteResponse(it.ID, it.CustomerID, it.ProductID, it.Title, it.ImageUrl, it.Price))
+	c.JSON(http.StatusOK, ToFavoritesPageResponse(page))
+}
+
+// PriceHistory godoc
+// @Summary Histórico de preços de um produto favoritado
+// @Description Lista as mudanças de preço observadas no catálogo, da mais recente para a mais antiga, com a variação desde que o produto foi favoritado.
+// @Tags Favorites
+// @Accept json
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Param productId path int true "Product ID"
+// @Param limit query int false "Máximo de registros (1-500)" default(100)
+// @Success 200 {object} PriceHistoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customer/{id}/favorites/{productId}/price-history [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *FavoriteHandler) PriceHistory(c *gin.Context) {
+	customerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || customerID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		return
+	}
+	productID, err := strconv.Atoi(c.Param("productId"))
+	if err != nil || productID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPriceHistoryLimit)))
+	if err != nil || limit < 1 || limit > maxPriceHistoryLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxPriceHistoryLimit)})
+		return
+	}
+	history, err := h.controller.PriceHistory(c.Request.Context(), uint(customerID), uint(productID), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "favorito não encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
-	c.JSON(http.StatusOK, out)
+	c.JSON(http.StatusOK, ToPriceHistoryResponse(history.Favorite, history.Points))
 }
 
 // Delete godoc
@@ -106,10 +181,13 @@ func (h *FavoriteHandler) List(c *gin.Context) {
 // @Param productId path int true "Product ID"
 // @Success 204 {object} nil
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /customer/{id}/favorites/{productId} [delete]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *FavoriteHandler) Delete(c *gin.Context) {
 	customerID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || customerID <= 0 {
@@ -122,7 +200,7 @@ func (h *FavoriteHandler) Delete(c *gin.Context) {
 		return
 	}
 	if err := h.controller.Remove(c.Request.Context(), uint(customerID), uint(productID)); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, domain.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "favorito não encontrado"})
 			return
 		}
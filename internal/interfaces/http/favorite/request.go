@@ -1,5 +1,45 @@
 package http_interfaces_favorite
 
+import (
+	"strings"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
+)
+
 type AddFavoriteRequest struct {
 	ProductID uint `json:"product_id" binding:"required,numeric,min=1"` // ProductID must be a positive integer
 }
+
+// ListFavoritesRequest são os parâmetros de query da listagem de favoritos.
+type ListFavoritesRequest struct {
+	Limit    int      `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor   string   `form:"cursor" binding:"max=512"`
+	Sort     string   `form:"sort" binding:"omitempty,oneof=created_at price title"`
+	Order    string   `form:"order" binding:"omitempty,oneof=asc desc"`
+	MinPrice *float32 `form:"min_price" binding:"omitempty,min=0"`
+	MaxPrice *float32 `form:"max_price" binding:"omitempty,min=0"`
+	Q        string   `form:"q" binding:"max=100"`
+	Category string   `form:"category" binding:"max=100"`
+}
+
+// ToQuery monta a consulta do domínio. Sem order explícito, created_at é
+// listado do mais recente para o mais antigo e as demais ordenações em
+// ordem crescente.
+func (r ListFavoritesRequest) ToQuery(customerID uint) domain.ListQuery {
+	sort := r.Sort
+	if sort == "" {
+		sort = domain.SortCreatedAt
+	}
+	desc := r.Order == "desc" || (r.Order == "" && sort == domain.SortCreatedAt)
+	return domain.ListQuery{
+		CustomerID: customerID,
+		Limit:      r.Limit,
+		Cursor:     r.Cursor,
+		Sort:       sort,
+		Desc:       desc,
+		MinPrice:   r.MinPrice,
+		MaxPrice:   r.MaxPrice,
+		Search:     strings.TrimSpace(r.Q),
+		Category:   strings.TrimSpace(r.Category),
+	}
+}
@@ -1,22 +1,92 @@
 package http_interfaces_favorite
 
+import (
+	"math"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
+	productdomain "github.com/vinihss/aiqfome/internal/domain/product"
+)
+
 type FavoriteResponse struct {
-	ID         uint    `json:"id"`
-	CustomerID uint    `json:"customer_id"`
-	ProductID  uint    `json:"product_id"`
-	Product    string  `json:"product"`
-	Title      string  `json:"title"`
-	ImageUrl   string  `json:"image_url"`
-	Price      float32 `json:"price"`
+	ID         uint   `json:"id"`
+	CustomerID uint   `json:"customer_id"`
+	ProductID  uint   `json:"product_id"`
+	Product    string `json:"product"`
+	Title      string `json:"title"`
+	ImageUrl   string `json:"image_url"`
+	// Price é o preço atual; FavoritedPrice, o preço quando o produto foi
+	// favoritado, e PriceChangePercent a variação entre os dois.
+	Price              float32 `json:"price"`
+	FavoritedPrice     float32 `json:"favorited_price"`
+	PriceChangePercent float64 `json:"price_change_percent"`
+	// Available é falso quando o produto saiu do catálogo; os demais campos
+	// trazem os últimos dados conhecidos.
+	Available bool      `json:"available"`
+	Category  string    `json:"category"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
-func ToFavoriteResponse(id, customerID, productID uint, title string, image string, price float32) FavoriteResponse {
+func ToFavoriteResponse(f domain.Favorite) FavoriteResponse {
 	return FavoriteResponse{
-		ID:         id,
-		CustomerID: customerID,
-		ProductID:  productID,
-		Title:      title,
-		ImageUrl:   image,
-		Price:      price,
+		ID:                 f.ID,
+		CustomerID:         f.CustomerID,
+		ProductID:          f.ProductID,
+		Title:              f.Title,
+		ImageUrl:           f.ImageUrl,
+		Price:              f.Price,
+		FavoritedPrice:     f.FavoritedPrice,
+		PriceChangePercent: roundPercent(f.PriceChangePercent()),
+		Available:          f.Available,
+		Category:           f.Category,
+		CreatedAt:          f.CreatedAt,
 	}
 }
+
+// FavoritesPageResponse é uma página da listagem. NextCursor vem vazio na
+// última página; Total conta todos os favoritos que atendem aos filtros.
+type FavoritesPageResponse struct {
+	Items      []FavoriteResponse `json:"items"`
+	NextCursor string             `json:"next_cursor"`
+	Total      int64              `json:"total"`
+}
+
+func ToFavoritesPageResponse(page domain.Page) FavoritesPageResponse {
+	items := make([]FavoriteResponse, 0, len(page.Items))
+	for _, it := range page.Items {
+		items = append(items, ToFavoriteResponse(it))
+	}
+	return FavoritesPageResponse{Items: items, NextCursor: page.NextCursor, Total: page.Total}
+}
+
+type PricePointResponse struct {
+	Price      float32   `json:"price"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+type PriceHistoryResponse struct {
+	ProductID          uint                 `json:"product_id"`
+	FavoritedPrice     float32              `json:"favorited_price"`
+	CurrentPrice       float32              `json:"current_price"`
+	PriceChangePercent float64              `json:"price_change_percent"`
+	History            []PricePointResponse `json:"history"`
+}
+
+func ToPriceHistoryResponse(f domain.Favorite, points []productdomain.PricePoint) PriceHistoryResponse {
+	history := make([]PricePointResponse, 0, len(points))
+	for _, p := range points {
+		history = append(history, PricePointResponse{Price: p.Price, RecordedAt: p.RecordedAt})
+	}
+	return PriceHistoryResponse{
+		ProductID:          f.ProductID,
+		FavoritedPrice:     f.FavoritedPrice,
+		CurrentPrice:       f.Price,
+		PriceChangePercent: roundPercent(f.PriceChangePercent()),
+		History:            history,
+	}
+}
+
+// roundPercent arredonda para duas casas decimais.
+func roundPercent(p float64) float64 {
+	return math.Round(p*100) / 100
+}
@@ -0,0 +1,76 @@
+package http_interfaces_health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vinihss/aiqfome/internal/infrastructure/health"
+)
+
+type stubChecker struct {
+	name string
+	err  error
+}
+
+func (s stubChecker) Name() string                { return s.name }
+func (s stubChecker) Check(context.Context) error { return s.err }
+
+func TestReadinessReturns503OnlyWhenDown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus health.Status
+		wantCode   int
+	}{
+		{"up", nil, health.StatusUp, http.StatusOK},
+		{"degraded", fmt.Errorf("%w: circuit breaker open", health.ErrDegraded), health.StatusDegraded, http.StatusOK},
+		{"down", errors.New("connection refused"), health.StatusDown, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := health.NewRegistry(time.Second)
+			registry.Register(stubChecker{name: "postgres"})
+			registry.Register(stubChecker{name: "dep", err: tt.err})
+			router := gin.New()
+			router.GET("/readyz", NewHealthHandler(registry).Readiness)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d", w.Code, tt.wantCode)
+			}
+			var report health.Report
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("decoding report: %v", err)
+			}
+			if report.Status != tt.wantStatus || len(report.Checks) != 2 {
+				t.Errorf("report = %+v, want status %q with both checks", report, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestLivenessIgnoresDependencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := health.NewRegistry(time.Second)
+	registry.Register(stubChecker{name: "postgres", err: errors.New("connection refused")})
+	router := gin.New()
+	router.GET("/healthz", NewHealthHandler(registry).Liveness)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("code = %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,19 @@
+package http_interfaces_product
+
+import (
+	"context"
+
+	usecase "github.com/vinihss/aiqfome/internal/usecases/product"
+)
+
+type ProductController struct {
+	purgeUC *usecase.PurgeCacheUseCase
+}
+
+func NewProductController(purge *usecase.PurgeCacheUseCase) *ProductController {
+	return &ProductController{purgeUC: purge}
+}
+
+func (c *ProductController) PurgeCache(ctx context.Context, productID uint) error {
+	return c.purgeUC.Execute(ctx, productID)
+}
@@ -0,0 +1,62 @@
+package http_interfaces_product
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductHandler struct {
+	controller *ProductController
+}
+
+func NewProductHandler(controller *ProductController) *ProductHandler {
+	return &ProductHandler{controller: controller}
+}
+
+// PurgeCache godoc
+// @Summary Esvaziar o cache de produtos
+// @Description Remove todos os produtos do cache em todas as instâncias
+// @Tags Products
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/products/cache [delete]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *ProductHandler) PurgeCache(c *gin.Context) {
+	if err := h.controller.PurgeCache(c.Request.Context(), 0); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PurgeProduct godoc
+// @Summary Remover um produto do cache
+// @Description Remove o produto do cache em todas as instâncias; a próxima consulta busca na API de produtos
+// @Tags Products
+// @Param productId path int true "Product ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/products/cache/{productId} [delete]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *ProductHandler) PurgeProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 64)
+	if err != nil || productID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	if err := h.controller.PurgeCache(c.Request.Context(), uint(productID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Hook representa um componente com início e fim controlados pelo servidor,
+// como pools de conexão ou workers em segundo plano. Qualquer uma das
+// funções pode ser nil.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Registry guarda os hooks na ordem em que foram registrados. Start executa
+// os hooks nessa ordem e Stop na ordem inversa, de forma que um componente
+// seja encerrado antes das dependências registradas antes dele.
+type Registry struct {
+	mutex   sync.Mutex
+	hooks   []Hook
+	started int
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Append(hook Hook) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.hooks = append(r.hooks, hook)
+}
+
+// Start executa os OnStart em ordem. Se algum falhar, os hooks já iniciados
+// são encerrados antes de o erro ser devolvido.
+func (r *Registry) Start(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for r.started < len(r.hooks) {
+		hook := r.hooks[r.started]
+		if hook.OnStart != nil {
+			if err := hook.OnStart(ctx); err != nil {
+				startErr := fmt.Errorf("starting %s: %w", hook.Name, err)
+				return errors.Join(startErr, r.stopLocked(ctx))
+			}
+		}
+		r.started++
+	}
+	return nil
+}
+
+// Stop executa os OnStop dos hooks iniciados em ordem inversa. Todos os hooks
+// são chamados mesmo que algum falhe; os erros são agregados.
+func (r *Registry) Stop(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.stopLocked(ctx)
+}
+
+func (r *Registry) stopLocked(ctx context.Context) error {
+	var errs []error
+	for ; r.started > 0; r.started-- {
+		hook := r.hooks[r.started-1]
+		if hook.OnStop == nil {
+			continue
+		}
+		if err := hook.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stopping %s: %w", hook.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Background cria um hook que roda run em uma goroutine entre Start e Stop.
+// O Stop cancela o contexto recebido por run e espera que ela termine.
+func Background(name string, run func(ctx context.Context) error) Hook {
+	var cancel context.CancelFunc
+	done := make(chan struct{})
+	return Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			go func() {
+				defer close(done)
+				if err := run(ctx); err != nil {
+					log.Printf("%s stopped: %v", name, err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// recorder registra a sequência de chamadas dos hooks.
+type recorder struct {
+	calls []string
+}
+
+func (r *recorder) hook(name string, startErr, stopErr error) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			r.calls = append(r.calls, "start "+name)
+			return startErr
+		},
+		OnStop: func(context.Context) error {
+			r.calls = append(r.calls, "stop "+name)
+			return stopErr
+		},
+	}
+}
+
+func TestRegistryStartsInOrderAndStopsInReverse(t *testing.T) {
+	rec := &recorder{}
+	registry := NewRegistry()
+	registry.Append(rec.hook("db", nil, nil))
+	registry.Append(rec.hook("cache", nil, nil))
+	registry.Append(Hook{Name: "no-op"}) // funções nil são ignoradas
+	registry.Append(rec.hook("worker", nil, nil))
+
+	if err := registry.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := registry.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	want := []string{"start db", "start cache", "start worker", "stop worker", "stop cache", "stop db"}
+	if !slices.Equal(rec.calls, want) {
+		t.Errorf("calls = %v, want %v", rec.calls, want)
+	}
+
+	// Um segundo Stop não encerra nada de novo.
+	rec.calls = nil
+	if err := registry.Stop(context.Background()); err != nil || len(rec.calls) != 0 {
+		t.Errorf("second Stop = %v, calls %v; want nothing", err, rec.calls)
+	}
+}
+
+func TestRegistryRollsBackWhenAHookFailsToStart(t *testing.T) {
+	rec := &recorder{}
+	failure := errors.New("connection refused")
+	registry := NewRegistry()
+	registry.Append(rec.hook("db", nil, nil))
+	registry.Append(rec.hook("cache", nil, nil))
+	registry.Append(rec.hook("broker", failure, nil))
+	registry.Append(rec.hook("worker", nil, nil))
+
+	err := registry.Start(context.Background())
+	if !errors.Is(err, failure) {
+		t.Fatalf("Start = %v, want %v", err, failure)
+	}
+
+	// O hook que falhou não é encerrado e o seguinte nem chega a iniciar.
+	want := []string{"start db", "start cache", "start broker", "stop cache", "stop db"}
+	if !slices.Equal(rec.calls, want) {
+		t.Errorf("calls = %v, want %v", rec.calls, want)
+	}
+
+	rec.calls = nil
+	if err := registry.Stop(context.Background()); err != nil || len(rec.calls) != 0 {
+		t.Errorf("Stop after rollback = %v, calls %v; want nothing", err, rec.calls)
+	}
+}
+
+func TestRegistryStopCallsEveryHookAndJoinsErrors(t *testing.T) {
+	rec := &recorder{}
+	errCache := errors.New("cache flush failed")
+	errDB := errors.New("db close failed")
+	registry := NewRegistry()
+	registry.Append(rec.hook("db", nil, errDB))
+	registry.Append(rec.hook("cache", nil, errCache))
+	registry.Append(rec.hook("worker", nil, nil))
+
+	if err := registry.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	err := registry.Stop(context.Background())
+	if !errors.Is(err, errCache) || !errors.Is(err, errDB) {
+		t.Errorf("Stop = %v, want both errors", err)
+	}
+	if len(rec.calls) != 6 {
+		t.Errorf("calls = %v, want every hook stopped", rec.calls)
+	}
+}
+
+func TestBackgroundStopCancelsAndWaits(t *testing.T) {
+	exited := make(chan struct{})
+	hook := Background("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		close(exited)
+		return ctx.Err()
+	})
+
+	if err := hook.OnStart(context.Background()); err != nil {
+		t.Fatalf("OnStart: %v", err)
+	}
+	if err := hook.OnStop(context.Background()); err != nil {
+		t.Fatalf("OnStop: %v", err)
+	}
+	select {
+	case <-exited:
+	default:
+		t.Error("OnStop returned before the goroutine exited")
+	}
+}
+
+func TestBackgroundStopHonoursDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	hook := Background("stuck", func(context.Context) error {
+		<-release
+		return nil
+	})
+
+	if err := hook.OnStart(context.Background()); err != nil {
+		t.Fatalf("OnStart: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := hook.OnStop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("OnStop = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
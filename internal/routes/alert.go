@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/vinihss/aiqfome/internal/domain/authentication"
+	http_interfaces_alert "github.com/vinihss/aiqfome/internal/interfaces/http/alert"
+	"github.com/vinihss/aiqfome/middlewares"
+)
+
+// Alertas de preço fazem parte dos favoritos e usam os mesmos escopos.
+func RegisterAlertRoutes(r gin.IRouter, handler *http_interfaces_alert.AlertHandler) {
+	read := middlewares.RequireScopes(authentication.ScopeFavoritesRead)
+	write := middlewares.RequireScopes(authentication.ScopeFavoritesWrite)
+	ownerOrAdmin := middlewares.RequireOwnerOrAdmin("id")
+
+	r.PUT("/customer/:id/favorites/:productId/alert", write, ownerOrAdmin, handler.SetRule)
+	r.GET("/customer/:id/favorites/:productId/alert", read, ownerOrAdmin, handler.GetRule)
+	r.DELETE("/customer/:id/favorites/:productId/alert", write, ownerOrAdmin, handler.DeleteRule)
+	r.GET("/customer/:id/alerts", read, ownerOrAdmin, handler.List)
+	r.POST("/customer/:id/alerts/:alertId/ack", write, ownerOrAdmin, handler.Acknowledge)
+}
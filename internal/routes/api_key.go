@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/vinihss/aiqfome/internal/domain/authentication"
+	http_interfaces_apikey "github.com/vinihss/aiqfome/internal/interfaces/http/apikey"
+	"github.com/vinihss/aiqfome/middlewares"
+)
+
+func RegisterAPIKeyRoutes(r gin.IRouter, handler *http_interfaces_apikey.APIKeyHandler) {
+	apiKeyGroup := r.Group("/admin/api-keys")
+	apiKeyGroup.Use(middlewares.RequireScopes(authentication.ScopeAPIKeysManage))
+	{
+		apiKeyGroup.POST("", handler.Create)
+		apiKeyGroup.GET("", handler.List)
+		apiKeyGroup.DELETE("/:id", handler.Revoke)
+	}
+}
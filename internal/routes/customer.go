@@ -3,16 +3,23 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 
+	"github.com/vinihss/aiqfome/internal/domain/authentication"
 	httpfav "github.com/vinihss/aiqfome/internal/interfaces/http/customer"
+	"github.com/vinihss/aiqfome/middlewares"
 )
 
-func RegisterCustomerRoutes(r *gin.Engine, handler *httpfav.CustomerHandler) {
+func RegisterCustomerRoutes(r gin.IRouter, handler *httpfav.CustomerHandler) {
 	customerGroup := r.Group("/customer")
 	{
-		customerGroup.POST("/", handler.Create)
-		customerGroup.GET("/:id", handler.FindByID)
-		customerGroup.GET("/", handler.GetAllCustomers)
-		customerGroup.PUT("/:id", handler.Update)
-		customerGroup.DELETE("/:id", handler.Delete)
+		read := middlewares.RequireScopes(authentication.ScopeCustomersRead)
+		write := middlewares.RequireScopes(authentication.ScopeCustomersWrite)
+		serviceOrAdmin := middlewares.RequireServiceOrAdmin()
+		ownerOrAdmin := middlewares.RequireOwnerOrAdmin("id")
+
+		customerGroup.POST("/", write, serviceOrAdmin, handler.Create)
+		customerGroup.GET("/:id", read, ownerOrAdmin, handler.FindByID)
+		customerGroup.GET("/", read, serviceOrAdmin, handler.GetAllCustomers)
+		customerGroup.PUT("/:id", write, ownerOrAdmin, handler.Update)
+		customerGroup.DELETE("/:id", write, ownerOrAdmin, handler.Delete)
 	}
 }
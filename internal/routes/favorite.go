@@ -3,13 +3,21 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 
+	"github.com/vinihss/aiqfome/internal/domain/authentication"
 	httpfav "github.com/vinihss/aiqfome/internal/interfaces/http/favorite"
+	"github.com/vinihss/aiqfome/middlewares"
 )
 
-func RegisterFavoriteRoutes(r *gin.Engine, handler *httpfav.FavoriteHandler) {
+// writeLimit se soma ao limite geral da API: cada inclusão pode gerar uma
+// chamada à API de produtos.
+func RegisterFavoriteRoutes(r gin.IRouter, handler *httpfav.FavoriteHandler, writeLimit gin.HandlerFunc) {
+	read := middlewares.RequireScopes(authentication.ScopeFavoritesRead)
+	write := middlewares.RequireScopes(authentication.ScopeFavoritesWrite)
+	ownerOrAdmin := middlewares.RequireOwnerOrAdmin("id")
 
-	r.POST("/customer/:id/favorites", handler.Create)
-	r.GET("/customer/:id/favorites", handler.List)
-	r.DELETE("/customer/:id/favorites/:productId", handler.Delete)
+	r.POST("/customer/:id/favorites", write, ownerOrAdmin, writeLimit, handler.Create)
+	r.GET("/customer/:id/favorites", read, ownerOrAdmin, handler.List)
+	r.DELETE("/customer/:id/favorites/:productId", write, ownerOrAdmin, writeLimit, handler.Delete)
+	r.GET("/customer/:id/favorites/:productId/price-history", read, ownerOrAdmin, handler.PriceHistory)
 
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/vinihss/aiqfome/internal/domain/authentication"
+	http_interfaces_product "github.com/vinihss/aiqfome/internal/interfaces/http/product"
+	"github.com/vinihss/aiqfome/middlewares"
+)
+
+func RegisterProductRoutes(r gin.IRouter, handler *http_interfaces_product.ProductHandler) {
+	productGroup := r.Group("/admin/products")
+	productGroup.Use(middlewares.RequireScopes(authentication.ScopeProductsManage))
+	{
+		productGroup.DELETE("/cache", handler.PurgeCache)
+		productGroup.DELETE("/cache/:productId", handler.PurgeProduct)
+	}
+}
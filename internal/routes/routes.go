@@ -2,58 +2,65 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 	"github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
-	"github.com/vinihss/aiqfome/internal/infrastructure/database/repositories"
-	"github.com/vinihss/aiqfome/internal/infrastructure/external_epis"
 	http_interfaces_authentication "github.com/vinihss/aiqfome/internal/interfaces/http/authentcation"
-	customeruse "github.com/vinihss/aiqfome/internal/usecases/customer"
-	favoriteuse "github.com/vinihss/aiqfome/internal/usecases/favorite"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 
 	_ "github.com/vinihss/aiqfome/docs"
+	http_interfaces_alert "github.com/vinihss/aiqfome/internal/interfaces/http/alert"
+	http_interfaces_apikey "github.com/vinihss/aiqfome/internal/interfaces/http/apikey"
 	"github.com/vinihss/aiqfome/internal/interfaces/http/customer"
 	"github.com/vinihss/aiqfome/internal/interfaces/http/favorite"
-	"github.com/vinihss/aiqfome/middlewares"
+	"github.com/vinihss/aiqfome/internal/interfaces/http/health"
+	http_interfaces_product "github.com/vinihss/aiqfome/internal/interfaces/http/product"
 )
 
+// Handlers reúne os handlers HTTP já montados pela raiz de composição.
+type Handlers struct {
+	Authentication *http_interfaces_authentication.AuthenticationHandler
+	Alert          *http_interfaces_alert.AlertHandler
+	APIKey         *http_interfaces_apikey.APIKeyHandler
+	Customer       *http_interfaces_customer.CustomerHandler
+	Favorite       *http_interfaces_favorite.FavoriteHandler
+	Health         *http_interfaces_health.HealthHandler
+	Product        *http_interfaces_product.ProductHandler
+}
+
+// RateLimits são os limitadores de cada grupo de rotas, já configurados.
+type RateLimits struct {
+	Public         gin.HandlerFunc
+	API            gin.HandlerFunc
+	FavoritesWrite gin.HandlerFunc
+}
+
 // SetupRoutes @title Aiqfome API
-func SetupRoutes(router *gin.Engine) {
+//
+// auth autentica tanto access tokens quanto API keys; cada grupo de rotas
+// declara os escopos exigidos, válidos para os dois tipos de credencial.
+func SetupRoutes(router *gin.Engine, auth gin.HandlerFunc, limits RateLimits, handlers Handlers) {
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	authController := http_interfaces_authentication.NewAuthenticationController()
-	router.POST("/authenticate", http_interfaces_authentication.NewAuthenticationHandler(authController).Authenticate)
-	db, _ := gorm.Open(postgres.Open("host=postgres user=postgres password=postgres dbname=favorites port=5432 sslmode=disable"), &gorm.Config{})
+	router.GET("/healthz", handlers.Health.Liveness)
+	router.GET("/readyz", handlers.Health.Readiness)
+	router.GET("/.well-known/jwks.json", handlers.Authentication.JWKS)
 
-	authorized := router.Group("/")
-	authorized.Use(middlewares.JWTAuth())
+	public := router.Group("/")
+	public.Use(limits.Public)
 	{
-		productClient := external_epis.NewFakeStoreClient()
-		rdb := redis.NewClient(&redis.Options{
-			Addr:     "redis:6379",
-			Password: "",
-			DB:       0,
-		})
-		favRepo := repositories.NewFavoriteRepository(db, rdb)
-		createFavoriteUC := favoriteuse.NewAddFavoriteUseCase(favRepo, productClient)
-		listFavoriteUC := favoriteuse.NewListFavoritesUseCase(favRepo)
-		removeFavoriteUC := favoriteuse.NewRemoveFavoriteUseCase(favRepo)
-		favController := http_interfaces_favorite.NewFavoriteController(createFavoriteUC, listFavoriteUC, removeFavoriteUC)
-		favHandler := http_interfaces_favorite.NewFavoriteHandler(favController)
-		RegisterFavoriteRoutes(router, favHandler)
-
-		custRepo := repositories.NewCustomerRepository(db)
-		createCustomerUC := customeruse.NewCreateCustomerUseCase(custRepo)
-		deleteCustomerUC := customeruse.NewDeleteCustomerUseCase(custRepo)
-		findCustomerUC := customeruse.NewFindCustomerUseCase(custRepo)
-		updateCustomerUC := customeruse.NewUpdateCustomerUseCase(custRepo)
-
-		custController := http_interfaces_customer.NewCustomerController(createCustomerUC, deleteCustomerUC, findCustomerUC, updateCustomerUC)
-		custHandler := http_interfaces_customer.NewCustomerHandler(custController)
-		RegisterCustomerRoutes(router, custHandler)
+		public.POST("/authenticate", handlers.Authentication.Authenticate)
+		public.POST("/authenticate/refresh", handlers.Authentication.Refresh)
+		public.POST("/register", handlers.Authentication.Register)
+	}
 
+	authorized := router.Group("/")
+	authorized.Use(auth, limits.API)
+	{
+		authorized.POST("/logout", handlers.Authentication.Logout)
+		RegisterFavoriteRoutes(authorized, handlers.Favorite, limits.FavoritesWrite)
+		RegisterAlertRoutes(authorized, handlers.Alert)
+		RegisterCustomerRoutes(authorized, handlers.Customer)
+		RegisterAPIKeyRoutes(authorized, handlers.APIKey)
+		RegisterProductRoutes(authorized, handlers.Product)
 	}
 
 }
@@ -0,0 +1,67 @@
+package alert
+
+import (
+	"context"
+	"log"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/alert"
+)
+
+// Detector avalia as regras de alerta de um produto sempre que um novo preço
+// é observado e entrega os alertas emitidos por todos os senders.
+type Detector struct {
+	repo    domain.Repository
+	senders []domain.Sender
+}
+
+func NewDetector(repo domain.Repository, senders ...domain.Sender) *Detector {
+	return &Detector{repo: repo, senders: senders}
+}
+
+// PriceObserved avalia as regras do produto. Cada regra é gravada com
+// controle de versão, então um mesmo cruzamento avaliado em paralelo gera um
+// único alerta.
+func (d *Detector) PriceObserved(ctx context.Context, productID uint, price float32) error {
+	rules, err := d.repo.RulesForProduct(productID)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		next, fired := rule.Evaluate(price)
+		if !fired && next.LastPrice == rule.LastPrice && next.Triggered == rule.Triggered {
+			continue
+		}
+
+		var alert *domain.Alert
+		if fired {
+			alert = &domain.Alert{
+				CustomerID: rule.CustomerID,
+				ProductID:  rule.ProductID,
+				RuleID:     rule.ID,
+				Kind:       rule.Kind,
+				Threshold:  rule.Threshold,
+				OldPrice:   rule.LastPrice,
+				NewPrice:   price,
+			}
+		}
+		stored, applied, err := d.repo.ApplyEvaluation(rule, next, alert)
+		if err != nil {
+			return err
+		}
+		if applied && stored != nil {
+			d.deliver(ctx, *stored)
+		}
+	}
+	return nil
+}
+
+// deliver tenta todos os senders; o alerta continua no feed mesmo que algum
+// falhe.
+func (d *Detector) deliver(ctx context.Context, a domain.Alert) {
+	for _, s := range d.senders {
+		if err := s.Send(ctx, a); err != nil {
+			log.Printf("price alerts: sending alert %d via %s: %v", a.ID, s.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package alert_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/alert"
+	"github.com/vinihss/aiqfome/internal/infrastructure/notifications"
+	usecase "github.com/vinihss/aiqfome/internal/usecases/alert"
+)
+
+// memoryAlerts implementa domain.Repository com o mesmo controle de versão
+// do Postgres: ApplyEvaluation só grava se a regra não mudou desde a leitura.
+type memoryAlerts struct {
+	mutex  sync.Mutex
+	rules  map[uint]domain.Rule
+	alerts []domain.Alert
+	// beforeApply, se informado, roda antes de cada ApplyEvaluation, fora do
+	// lock, para simular outra instância avaliando a mesma regra.
+	beforeApply func(rule domain.Rule)
+}
+
+func newMemoryAlerts(rules ...domain.Rule) *memoryAlerts {
+	r := &memoryAlerts{rules: map[uint]domain.Rule{}}
+	for i, rule := range rules {
+		rule.ID = uint(i + 1)
+		r.rules[rule.ID] = rule
+	}
+	return r
+}
+
+func (r *memoryAlerts) SaveRule(rule domain.Rule) (domain.Rule, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	rule.ID = uint(len(r.rules) + 1)
+	r.rules[rule.ID] = rule
+	return rule, nil
+}
+
+func (r *memoryAlerts) FindRule(customerID, productID uint) (domain.Rule, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, rule := range r.rules {
+		if rule.CustomerID == customerID && rule.ProductID == productID {
+			return rule, nil
+		}
+	}
+	return domain.Rule{}, domain.ErrNotFound
+}
+
+func (r *memoryAlerts) DeleteRule(customerID, productID uint) error {
+	rule, err := r.FindRule(customerID, productID)
+	if err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.rules, rule.ID)
+	return nil
+}
+
+func (r *memoryAlerts) RulesForProduct(productID uint) ([]domain.Rule, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var out []domain.Rule
+	for id := uint(1); id <= uint(len(r.rules)); id++ {
+		if rule, ok := r.rules[id]; ok && rule.ProductID == productID {
+			out = append(out, rule)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryAlerts) ApplyEvaluation(current, next domain.Rule, alert *domain.Alert) (*domain.Alert, bool, error) {
+	if r.beforeApply != nil {
+		r.beforeApply(current)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.rules[current.ID].Version != current.Version {
+		return nil, false, nil
+	}
+	next.Version = current.Version + 1
+	r.rules[current.ID] = next
+	if alert == nil {
+		return nil, true, nil
+	}
+	stored := *alert
+	stored.ID = uint(len(r.alerts) + 1)
+	stored.CreatedAt = time.Now()
+	r.alerts = append(r.alerts, stored)
+	return &stored, true, nil
+}
+
+func (r *memoryAlerts) ListAlerts(customerID uint, _ bool, _ int) ([]domain.Alert, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var out []domain.Alert
+	for _, a := range r.alerts {
+		if a.CustomerID == customerID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryAlerts) Acknowledge(uint, uint, time.Time) (domain.Alert, error) {
+	return domain.Alert{}, domain.ErrNotFound
+}
+
+// deliveries lê as linhas JSON gravadas pelo LogSender.
+func deliveries(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid delivery %q: %v", line, err)
+		}
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+func TestDetectorDeliversOncePerCrossing(t *testing.T) {
+	repo := newMemoryAlerts(
+		domain.Rule{CustomerID: 1, ProductID: 7, Kind: domain.KindBelowPrice, Threshold: 80, LastPrice: 100},
+		domain.Rule{CustomerID: 2, ProductID: 7, Kind: domain.KindDropPercent, Threshold: 10, ReferencePrice: 100, LastPrice: 100},
+		domain.Rule{CustomerID: 3, ProductID: 8, Kind: domain.KindAnyDrop, LastPrice: 100},
+	)
+	var out bytes.Buffer
+	detector := usecase.NewDetector(repo, notifications.NewLogSender(&out))
+
+	// below_price cruza em 79 e de novo em 78; drop_percent cruza em 85 e
+	// de novo em 78. O produto 8 não muda de preço.
+	for _, price := range []float32{95, 85, 79, 75, 85, 95, 78} {
+		if err := detector.PriceObserved(context.Background(), 7, price); err != nil {
+			t.Fatalf("PriceObserved(%v): %v", price, err)
+		}
+	}
+
+	got := deliveries(t, &out)
+	want := []struct {
+		customer float64
+		newPrice float64
+	}{{2, 85}, {1, 79}, {1, 78}, {2, 78}}
+	if len(got) != len(want) {
+		t.Fatalf("deliveries = %v, want %d", got, len(want))
+	}
+	for i, w := range want {
+		if got[i]["customer_id"] != w.customer || got[i]["new_price"] != w.newPrice {
+			t.Errorf("delivery %d = %v, want customer %v at %v", i, got[i], w.customer, w.newPrice)
+		}
+	}
+	if len(repo.alerts) != len(want) {
+		t.Errorf("stored alerts = %d, want %d", len(repo.alerts), len(want))
+	}
+}
+
+func TestDetectorSkipsStaleEvaluation(t *testing.T) {
+	repo := newMemoryAlerts(domain.Rule{CustomerID: 1, ProductID: 7, Kind: domain.KindBelowPrice, Threshold: 80, LastPrice: 100})
+	var out bytes.Buffer
+	detector := usecase.NewDetector(repo, notifications.NewLogSender(&out))
+
+	// Outra instância grava a regra entre a leitura e a gravação desta.
+	var once sync.Once
+	repo.beforeApply = func(rule domain.Rule) {
+		once.Do(func() {
+			repo.mutex.Lock()
+			defer repo.mutex.Unlock()
+			stored := repo.rules[rule.ID]
+			stored.Version++
+			repo.rules[rule.ID] = stored
+		})
+	}
+
+	if err := detector.PriceObserved(context.Background(), 7, 79); err != nil {
+		t.Fatalf("PriceObserved: %v", err)
+	}
+	if out.Len() != 0 || len(repo.alerts) != 0 {
+		t.Errorf("stale evaluation delivered %q and stored %d alerts", out.String(), len(repo.alerts))
+	}
+}
+
+func TestDetectorConcurrentObservationsDeliverOnce(t *testing.T) {
+	repo := newMemoryAlerts(domain.Rule{CustomerID: 1, ProductID: 7, Kind: domain.KindBelowPrice, Threshold: 80, LastPrice: 100})
+	var out bytes.Buffer
+	detector := usecase.NewDetector(repo, notifications.NewLogSender(&out))
+
+	// Todas as goroutines leem a regra antes de qualquer uma gravar.
+	const observers = 8
+	var read sync.WaitGroup
+	read.Add(observers)
+	repo.beforeApply = func(domain.Rule) {
+		read.Done()
+		read.Wait()
+	}
+
+	var wg sync.WaitGroup
+	for range observers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := detector.PriceObserved(context.Background(), 7, 79); err != nil {
+				t.Errorf("PriceObserved: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := deliveries(t, &out); len(got) != 1 {
+		t.Errorf("deliveries = %d, want 1: %v", len(got), got)
+	}
+}
@@ -0,0 +1,30 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/alert"
+)
+
+// FeedUseCase lista e reconhece os alertas emitidos para um cliente.
+type FeedUseCase struct {
+	repo domain.Repository
+}
+
+func NewFeedUseCase(repo domain.Repository) *FeedUseCase {
+	return &FeedUseCase{repo: repo}
+}
+
+func (uc *FeedUseCase) List(_ context.Context, customerID uint, unacknowledgedOnly bool, limit int) ([]domain.Alert, error) {
+	return uc.repo.ListAlerts(customerID, unacknowledgedOnly, limit)
+}
+
+func (uc *FeedUseCase) Acknowledge(_ context.Context, customerID, alertID uint) (domain.Alert, error) {
+	a, err := uc.repo.Acknowledge(customerID, alertID, time.Now())
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Alert{}, ErrAlertNotFound
+	}
+	return a, err
+}
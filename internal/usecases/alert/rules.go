@@ -0,0 +1,87 @@
+package alert
+
+import (
+	"context"
+	"errors"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/alert"
+	favoritedomain "github.com/vinihss/aiqfome/internal/domain/favorite"
+)
+
+var (
+	ErrInvalidRule      = errors.New("regra de alerta inválida")
+	ErrRuleNotFound     = errors.New("regra de alerta não encontrada")
+	ErrFavoriteNotFound = errors.New("favorito não encontrado")
+	ErrAlertNotFound    = errors.New("alerta não encontrado")
+)
+
+type SetRuleInput struct {
+	CustomerID uint
+	ProductID  uint
+	Kind       string
+	// Threshold é o preço para below_price e a queda percentual para
+	// drop_percent; é ignorado em any_drop.
+	Threshold float32
+}
+
+// RuleUseCase gerencia a regra de alerta de preço de cada favorito.
+type RuleUseCase struct {
+	repo      domain.Repository
+	favorites favoritedomain.Repository
+}
+
+func NewRuleUseCase(repo domain.Repository, favorites favoritedomain.Repository) *RuleUseCase {
+	return &RuleUseCase{repo: repo, favorites: favorites}
+}
+
+// Set cria ou substitui a regra do favorito. O preço atual do favorito é a
+// referência das quedas seguintes.
+func (uc *RuleUseCase) Set(_ context.Context, in SetRuleInput) (domain.Rule, error) {
+	switch in.Kind {
+	case domain.KindAnyDrop:
+		in.Threshold = 0
+	case domain.KindBelowPrice:
+		if in.Threshold <= 0 {
+			return domain.Rule{}, ErrInvalidRule
+		}
+	case domain.KindDropPercent:
+		if in.Threshold <= 0 || in.Threshold >= 100 {
+			return domain.Rule{}, ErrInvalidRule
+		}
+	default:
+		return domain.Rule{}, ErrInvalidRule
+	}
+
+	fav, err := uc.favorites.Find(in.CustomerID, in.ProductID)
+	if errors.Is(err, favoritedomain.ErrNotFound) {
+		return domain.Rule{}, ErrFavoriteNotFound
+	}
+	if err != nil {
+		return domain.Rule{}, err
+	}
+
+	return uc.repo.SaveRule(domain.Rule{
+		CustomerID:     in.CustomerID,
+		ProductID:      in.ProductID,
+		Kind:           in.Kind,
+		Threshold:      in.Threshold,
+		ReferencePrice: fav.Price,
+		LastPrice:      fav.Price,
+	})
+}
+
+func (uc *RuleUseCase) Get(_ context.Context, customerID, productID uint) (domain.Rule, error) {
+	rule, err := uc.repo.FindRule(customerID, productID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Rule{}, ErrRuleNotFound
+	}
+	return rule, err
+}
+
+func (uc *RuleUseCase) Delete(_ context.Context, customerID, productID uint) error {
+	err := uc.repo.DeleteRule(customerID, productID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return ErrRuleNotFound
+	}
+	return err
+}
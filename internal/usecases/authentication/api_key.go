@@ -0,0 +1,145 @@
+package authentication
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/authentication"
+)
+
+var (
+	ErrInvalidAPIKey = errors.New("API key inválida")
+	ErrInvalidScope  = errors.New("escopo inválido")
+	// ErrScopeNotGranted indica que o criador pediu um escopo que ele mesmo
+	// não tem.
+	ErrScopeNotGranted = errors.New("escopo não concedido ao criador da chave")
+	ErrAPIKeyNotFound  = errors.New("API key não encontrada")
+	ErrInvalidExpiry   = errors.New("expires_at deve estar no futuro")
+)
+
+// apiKeyPrefix identifica as chaves desta API em logs e scanners de segredo.
+const apiKeyPrefix = "aqf"
+
+// lastUsedInterval limita a frequência com que last_used_at é gravado, para
+// que serviços com muito tráfego não gerem um UPDATE por requisição.
+const lastUsedInterval = time.Minute
+
+type CreateAPIKeyInput struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+	// Creator é quem está criando a chave. Só administradores concedem
+	// escopos que não têm; os demais ficam limitados aos próprios escopos.
+	Creator domain.Principal
+}
+
+// APIKeyUseCase cria, lista, revoga e autentica as API keys usadas por
+// sistemas internos. As chaves têm o formato aqf_<prefixo>_<segredo> e só o
+// hash SHA-256 é persistido.
+type APIKeyUseCase struct {
+	repo domain.APIKeyRepository
+	now  func() time.Time
+
+	mu       sync.Mutex
+	lastSeen map[uint]time.Time
+}
+
+func NewAPIKeyUseCase(repo domain.APIKeyRepository) *APIKeyUseCase {
+	return &APIKeyUseCase{repo: repo, now: time.Now, lastSeen: map[uint]time.Time{}}
+}
+
+// Create gera uma nova chave e devolve o valor em texto, que não pode ser
+// recuperado depois. Uma API key com api_keys:manage só cria chaves com um
+// subconjunto dos próprios escopos, para não escalar privilégios.
+func (uc *APIKeyUseCase) Create(input CreateAPIKeyInput) (domain.APIKey, string, error) {
+	if len(input.Scopes) == 0 {
+		return domain.APIKey{}, "", fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	isAdmin := input.Creator.Kind == domain.PrincipalCustomer && input.Creator.HasRole(domain.RoleAdmin)
+	for _, scope := range input.Scopes {
+		if !domain.IsValidScope(scope) {
+			return domain.APIKey{}, "", fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+		if !isAdmin && !input.Creator.HasScope(scope) {
+			return domain.APIKey{}, "", fmt.Errorf("%w: %q", ErrScopeNotGranted, scope)
+		}
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(uc.now()) {
+		return domain.APIKey{}, "", ErrInvalidExpiry
+	}
+
+	prefix := hex.EncodeToString(randomBytes(4))
+	plain := apiKeyPrefix + "_" + prefix + "_" + newOpaqueToken()
+
+	key, err := uc.repo.Create(domain.APIKey{
+		Name:      input.Name,
+		Prefix:    prefix,
+		Scopes:    input.Scopes,
+		ExpiresAt: input.ExpiresAt,
+	}, hashToken(plain))
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	return key, plain, nil
+}
+
+func (uc *APIKeyUseCase) List() ([]domain.APIKey, error) {
+	return uc.repo.List()
+}
+
+func (uc *APIKeyUseCase) Revoke(id uint) error {
+	err := uc.repo.Revoke(id, uc.now())
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return ErrAPIKeyNotFound
+	}
+	return err
+}
+
+// Authenticate valida a chave recebida e devolve o principal com os escopos
+// concedidos a ela.
+func (uc *APIKeyUseCase) Authenticate(plain string) (domain.Principal, error) {
+	if !strings.HasPrefix(plain, apiKeyPrefix+"_") {
+		return domain.Principal{}, ErrInvalidAPIKey
+	}
+
+	key, err := uc.repo.FindByHash(hashToken(plain))
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return domain.Principal{}, ErrInvalidAPIKey
+		}
+		return domain.Principal{}, err
+	}
+
+	now := uc.now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !key.ExpiresAt.After(now)) {
+		return domain.Principal{}, ErrInvalidAPIKey
+	}
+
+	uc.touch(key.ID, now)
+
+	return domain.Principal{
+		Kind:     domain.PrincipalAPIKey,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+func (uc *APIKeyUseCase) touch(id uint, now time.Time) {
+	uc.mu.Lock()
+	if last, ok := uc.lastSeen[id]; ok && now.Sub(last) < lastUsedInterval {
+		uc.mu.Unlock()
+		return
+	}
+	uc.lastSeen[id] = now
+	uc.mu.Unlock()
+
+	// Falhar ao registrar o uso não deve negar uma chave válida.
+	if err := uc.repo.TouchLastUsed(id, now); err != nil {
+		log.Printf("api key %d: updating last_used_at: %v", id, err)
+	}
+}
@@ -0,0 +1,80 @@
+package authentication
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/authentication"
+)
+
+// memoryAPIKeys guarda as chaves criadas, sem banco.
+type memoryAPIKeys struct {
+	keys []domain.APIKey
+}
+
+func (r *memoryAPIKeys) Create(k domain.APIKey, _ string) (domain.APIKey, error) {
+	k.ID = uint(len(r.keys) + 1)
+	r.keys = append(r.keys, k)
+	return k, nil
+}
+
+func (r *memoryAPIKeys) FindByHash(string) (domain.APIKey, error) {
+	return domain.APIKey{}, domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryAPIKeys) List() ([]domain.APIKey, error)      { return r.keys, nil }
+func (r *memoryAPIKeys) Revoke(uint, time.Time) error        { return domain.ErrAPIKeyNotFound }
+func (r *memoryAPIKeys) TouchLastUsed(uint, time.Time) error { return nil }
+
+func TestCreateAPIKeyLimitsScopesToTheCreator(t *testing.T) {
+	admin := domain.Principal{
+		Kind:   domain.PrincipalCustomer,
+		Roles:  []string{domain.RoleAdmin},
+		Scopes: domain.ScopesForRoles([]string{domain.RoleAdmin}),
+	}
+	manager := domain.Principal{
+		Kind:   domain.PrincipalAPIKey,
+		Scopes: []string{domain.ScopeAPIKeysManage, domain.ScopeFavoritesRead},
+	}
+	// Um papel admin na chave não vale: só clientes têm papéis.
+	forged := domain.Principal{
+		Kind:   domain.PrincipalAPIKey,
+		Roles:  []string{domain.RoleAdmin},
+		Scopes: []string{domain.ScopeAPIKeysManage},
+	}
+
+	tests := []struct {
+		name    string
+		creator domain.Principal
+		scopes  []string
+		wantErr error
+	}{
+		{"admin grants any scope", admin, []string{domain.ScopeProductsManage, domain.ScopeCustomersWrite}, nil},
+		{"api key grants its own scopes", manager, []string{domain.ScopeFavoritesRead}, nil},
+		{"api key grants api_keys:manage it holds", manager, []string{domain.ScopeAPIKeysManage}, nil},
+		{"api key cannot grant a scope it lacks", manager, []string{domain.ScopeFavoritesRead, domain.ScopeProductsManage}, ErrScopeNotGranted},
+		{"roles on an api key are ignored", forged, []string{domain.ScopeProductsManage}, ErrScopeNotGranted},
+		{"unknown scope is invalid", admin, []string{"favorites:admin"}, ErrInvalidScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryAPIKeys{}
+			uc := NewAPIKeyUseCase(repo)
+
+			key, plain, err := uc.Create(CreateAPIKeyInput{Name: "crm", Scopes: tt.scopes, Creator: tt.creator})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(repo.keys) != 0 {
+					t.Errorf("a key was stored despite the error: %+v", repo.keys)
+				}
+				return
+			}
+			if plain == "" || len(key.Scopes) != len(tt.scopes) {
+				t.Errorf("Create = %+v, %q; want a key with scopes %v", key, plain, tt.scopes)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package authentication
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/authentication"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("email ou senha inválidos")
+)
+
+// dummyHash é comparado quando o email não existe, para que o tempo de
+// resposta não revele quais emails estão cadastrados.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("aiqfome-dummy-password"), bcrypt.DefaultCost)
+
+type AuthenticateUseCase struct {
+	repo domain.Repository
+}
+
+func NewAuthenticateUseCase(repo domain.Repository) *AuthenticateUseCase {
+	return &AuthenticateUseCase{repo: repo}
+}
+
+// Execute valida email e senha e devolve a identidade do cliente autenticado.
+func (uc *AuthenticateUseCase) Execute(email, password string) (domain.Identity, error) {
+	credential, err := uc.repo.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, domain.ErrCredentialNotFound) {
+			_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+			return domain.Identity{}, ErrInvalidCredentials
+		}
+		return domain.Identity{}, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(credential.PasswordHash), []byte(password)); err != nil {
+		return domain.Identity{}, ErrInvalidCredentials
+	}
+
+	return domain.Identity{
+		CustomerID: credential.CustomerID,
+		Roles:      credential.Roles,
+	}, nil
+}
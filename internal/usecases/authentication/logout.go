@@ -0,0 +1,36 @@
+package authentication
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/authentication"
+)
+
+type LogoutInput struct {
+	CustomerID   uint
+	TokenID      string
+	ExpiresAt    time.Time
+	RefreshToken string
+}
+
+type LogoutUseCase struct {
+	denylist domain.TokenDenylist
+	refresh  *RefreshUseCase
+}
+
+func NewLogoutUseCase(denylist domain.TokenDenylist, refresh *RefreshUseCase) *LogoutUseCase {
+	return &LogoutUseCase{denylist: denylist, refresh: refresh}
+}
+
+// Execute revoga o access token atual e, se informado, a família do refresh
+// token da mesma sessão.
+func (uc *LogoutUseCase) Execute(ctx context.Context, input LogoutInput) error {
+	if err := uc.denylist.Revoke(ctx, input.TokenID, input.ExpiresAt); err != nil {
+		return err
+	}
+	if input.RefreshToken == "" {
+		return nil
+	}
+	return uc.refresh.Revoke(ctx, input.CustomerID, input.RefreshToken)
+}
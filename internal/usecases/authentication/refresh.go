@@ -0,0 +1,99 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/authentication"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token inválido ou expirado")
+	ErrRefreshTokenReused  = errors.New("refresh token reutilizado; sessão revogada")
+)
+
+// RefreshUseCase emite e rotaciona refresh tokens. Cada uso devolve um novo
+// token da mesma família; reapresentar um token já usado revoga a família
+// inteira, pois indica que ele vazou.
+type RefreshUseCase struct {
+	store domain.RefreshTokenStore
+	ttl   time.Duration
+}
+
+func NewRefreshUseCase(store domain.RefreshTokenStore, ttl time.Duration) *RefreshUseCase {
+	return &RefreshUseCase{store: store, ttl: ttl}
+}
+
+// Issue inicia uma nova família de refresh tokens para a identidade.
+func (uc *RefreshUseCase) Issue(ctx context.Context, identity domain.Identity) (string, error) {
+	return uc.issue(ctx, identity, NewTokenID())
+}
+
+// Execute troca um refresh token válido pela identidade e um novo token.
+func (uc *RefreshUseCase) Execute(ctx context.Context, token string) (domain.Identity, string, error) {
+	hash := hashToken(token)
+
+	session, err := uc.store.Find(ctx, hash)
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshSessionNotFound) {
+			return domain.Identity{}, "", ErrInvalidRefreshToken
+		}
+		return domain.Identity{}, "", err
+	}
+
+	revoked, err := uc.store.IsFamilyRevoked(ctx, session.FamilyID)
+	if err != nil {
+		return domain.Identity{}, "", err
+	}
+	if revoked {
+		return domain.Identity{}, "", ErrInvalidRefreshToken
+	}
+
+	firstUse, err := uc.store.MarkUsed(ctx, hash, session.ExpiresAt)
+	if err != nil {
+		return domain.Identity{}, "", err
+	}
+	if !firstUse {
+		if err := uc.store.RevokeFamily(ctx, session.FamilyID, time.Now().Add(uc.ttl)); err != nil {
+			return domain.Identity{}, "", err
+		}
+		return domain.Identity{}, "", ErrRefreshTokenReused
+	}
+
+	identity := domain.Identity{CustomerID: session.CustomerID, Roles: session.Roles}
+	next, err := uc.issue(ctx, identity, session.FamilyID)
+	if err != nil {
+		return domain.Identity{}, "", err
+	}
+	return identity, next, nil
+}
+
+// Revoke encerra a família do token informado, desde que pertença ao cliente.
+func (uc *RefreshUseCase) Revoke(ctx context.Context, customerID uint, token string) error {
+	session, err := uc.store.Find(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshSessionNotFound) {
+			return nil
+		}
+		return err
+	}
+	if session.CustomerID != customerID {
+		return nil
+	}
+	return uc.store.RevokeFamily(ctx, session.FamilyID, time.Now().Add(uc.ttl))
+}
+
+func (uc *RefreshUseCase) issue(ctx context.Context, identity domain.Identity, familyID string) (string, error) {
+	token := newOpaqueToken()
+	err := uc.store.Save(ctx, hashToken(token), domain.RefreshSession{
+		FamilyID:   familyID,
+		CustomerID: identity.CustomerID,
+		Roles:      identity.Roles,
+		ExpiresAt:  time.Now().Add(uc.ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
@@ -0,0 +1,135 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/authentication"
+	"github.com/vinihss/aiqfome/internal/infrastructure/redistest"
+	"github.com/vinihss/aiqfome/internal/infrastructure/sessions"
+)
+
+func newRefreshUseCase(t *testing.T) *RefreshUseCase {
+	t.Helper()
+	store := sessions.NewRefreshTokenStore(redistest.NewServer(t).Client(t))
+	return NewRefreshUseCase(store, time.Hour)
+}
+
+func issueToken(t *testing.T, uc *RefreshUseCase, customerID uint) string {
+	t.Helper()
+	token, err := uc.Issue(context.Background(), domain.Identity{CustomerID: customerID, Roles: []string{domain.RoleCustomer}})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	return token
+}
+
+func TestRefreshRotatesTokenOnce(t *testing.T) {
+	uc := newRefreshUseCase(t)
+	ctx := context.Background()
+	first := issueToken(t, uc, 7)
+
+	identity, second, err := uc.Execute(ctx, first)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if identity.CustomerID != 7 || len(identity.Roles) != 1 || second == "" || second == first {
+		t.Fatalf("Execute = %+v, %q; want customer 7 and a new token", identity, second)
+	}
+
+	// O token novo segue a rotação normalmente.
+	if _, _, err := uc.Execute(ctx, second); err != nil {
+		t.Errorf("Execute with the rotated token: %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	uc := newRefreshUseCase(t)
+	ctx := context.Background()
+	first := issueToken(t, uc, 7)
+	other := issueToken(t, uc, 7) // outro login, outra família
+
+	_, second, err := uc.Execute(ctx, first)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if _, _, err := uc.Execute(ctx, first); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reusing the old token = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, _, err := uc.Execute(ctx, second); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("token of the revoked family = %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, _, err := uc.Execute(ctx, other); err != nil {
+		t.Errorf("token of another family: %v", err)
+	}
+}
+
+func TestRefreshConcurrentRotationHasOneWinner(t *testing.T) {
+	uc := newRefreshUseCase(t)
+	token := issueToken(t, uc, 7)
+
+	const callers = 8
+	var (
+		wg              sync.WaitGroup
+		mutex           sync.Mutex
+		rotated, reused int
+	)
+	start := make(chan struct{})
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, _, err := uc.Execute(context.Background(), token)
+			mutex.Lock()
+			defer mutex.Unlock()
+			switch {
+			case err == nil:
+				rotated++
+			case errors.Is(err, ErrRefreshTokenReused), errors.Is(err, ErrInvalidRefreshToken):
+				reused++
+			default:
+				t.Errorf("Execute: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if rotated != 1 || reused != callers-1 {
+		t.Errorf("rotated = %d, rejected = %d; want exactly one rotation", rotated, reused)
+	}
+}
+
+func TestRefreshRejectsUnknownToken(t *testing.T) {
+	uc := newRefreshUseCase(t)
+	if _, _, err := uc.Execute(context.Background(), "nao-existe"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Execute = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRevokeIgnoresAnotherCustomersToken(t *testing.T) {
+	uc := newRefreshUseCase(t)
+	ctx := context.Background()
+	token := issueToken(t, uc, 7)
+
+	if err := uc.Revoke(ctx, 8, token); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, _, err := uc.Execute(ctx, token); err != nil {
+		t.Errorf("token revoked by another customer: %v", err)
+	}
+
+	// O próprio dono consegue revogar.
+	token = issueToken(t, uc, 7)
+	if err := uc.Revoke(ctx, 7, token); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, _, err := uc.Execute(ctx, token); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("token revoked by its owner = %v, want ErrInvalidRefreshToken", err)
+	}
+}
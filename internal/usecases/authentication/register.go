@@ -0,0 +1,42 @@
+package authentication
+
+import (
+	"golang.org/x/crypto/bcrypt"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/authentication"
+	"github.com/vinihss/aiqfome/internal/domain/customer"
+)
+
+type RegisterInput struct {
+	Name     string
+	Email    string
+	Password string
+}
+
+type RegisterUseCase struct {
+	repo domain.Repository
+}
+
+func NewRegisterUseCase(repo domain.Repository) *RegisterUseCase {
+	return &RegisterUseCase{repo: repo}
+}
+
+// Execute cadastra o cliente com o papel padrão de cliente. Administradores
+// não podem ser criados por este fluxo.
+func (uc *RegisterUseCase) Execute(input RegisterInput) (customer.Customer, domain.Identity, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return customer.Customer{}, domain.Identity{}, err
+	}
+
+	roles := []string{domain.RoleCustomer}
+	c, err := uc.repo.Register(customer.Customer{
+		Name:  input.Name,
+		Email: input.Email,
+	}, string(hash), roles)
+	if err != nil {
+		return customer.Customer{}, domain.Identity{}, err
+	}
+
+	return c, domain.Identity{CustomerID: c.ID, Roles: roles}, nil
+}
@@ -0,0 +1,29 @@
+package authentication
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// NewTokenID gera um identificador aleatório para o jti dos access tokens.
+func NewTokenID() string {
+	return hex.EncodeToString(randomBytes(16))
+}
+
+func newOpaqueToken() string {
+	return base64.RawURLEncoding.EncodeToString(randomBytes(32))
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	// crypto/rand.Read nunca falha nas plataformas suportadas.
+	_, _ = rand.Read(b)
+	return b
+}
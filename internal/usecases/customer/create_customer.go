@@ -9,7 +9,7 @@ type CustomerRepository interface {
 	Delete(id uint) error
 	FindByID(id uint) (customer.Customer, error)
 	Update(entity customer.Customer) (customer.Customer, error)
-	FindAll(int, size int) ([]customer.Customer, error)
+	List(q customer.ListQuery) (customer.Page, error)
 }
 
 type CreateCustomerInput struct {
@@ -20,11 +20,12 @@ func (uc *FindCustomerUseCase) Execute(id uint) (customer.Customer, error) {
 	return entity, nil
 }
 
-func (uc *FindCustomerUseCase) ExecuteAll(page, size int) ([]customer.Customer, error) {
-	offset := (page - 1) * size
-	customers, err := uc.repo.FindAll(offset, size)
-	if err != nil {
-		return nil, err
+// ExecuteAll aplica os valores padrão da listagem e rejeita consultas fora
+// dos limites com customer.ErrInvalidQuery.
+func (uc *FindCustomerUseCase) ExecuteAll(q customer.ListQuery) (customer.Page, error) {
+	q = q.WithDefaults()
+	if err := q.Validate(); err != nil {
+		return customer.Page{}, err
 	}
-	return customers, nil
+	return uc.repo.List(q)
 }
@@ -3,9 +3,11 @@ package favorite
 import (
 	"context"
 	"errors"
+	"log"
+	"time"
 
 	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
-	"github.com/vinihss/aiqfome/internal/infrastructure/external_epis"
+	productdomain "github.com/vinihss/aiqfome/internal/domain/product"
 )
 
 var (
@@ -13,17 +15,18 @@ var (
 )
 
 type AddFavoriteUseCase struct {
-	repo   domain.Repository
-	client external_epis.ProductClient
+	repo    domain.Repository
+	catalog productdomain.Catalog
+	prices  productdomain.PriceHistoryRepository
 }
 
-func NewAddFavoriteUseCase(repo domain.Repository, client external_epis.ProductClient) *AddFavoriteUseCase {
-	return &AddFavoriteUseCase{repo: repo, client: client}
+func NewAddFavoriteUseCase(repo domain.Repository, catalog productdomain.Catalog, prices productdomain.PriceHistoryRepository) *AddFavoriteUseCase {
+	return &AddFavoriteUseCase{repo: repo, catalog: catalog, prices: prices}
 }
 
 func (uc *AddFavoriteUseCase) Execute(ctx context.Context, customerID uint, productID uint) (domain.Favorite, error) {
 
-	product, err := uc.client.GetProduct(ctx, productID)
+	product, err := uc.catalog.GetProduct(ctx, productID)
 	if err != nil {
 		return domain.Favorite{}, err
 	}
@@ -36,13 +39,28 @@ func (uc *AddFavoriteUseCase) Execute(ctx context.Context, customerID uint, prod
 		return domain.Favorite{}, ErrAlreadyFavorited
 	}
 
+	syncedAt := time.Now()
+	recordPrice(uc.prices, product, syncedAt)
+
 	fav := domain.Favorite{
-		CustomerID: customerID,
-		ProductID:  productID,
-		Title:      product.Title,
-		ImageUrl:   product.Image,
-		Price:      product.Price,
+		CustomerID:   customerID,
+		ProductID:    productID,
+		Title:        product.Title,
+		ImageUrl:     product.Image,
+		Category:     product.Category,
+		Price:        product.Price,
+		Available:    true,
+		LastSyncedAt: &syncedAt,
 	}
 
 	return uc.repo.Create(fav)
 }
+
+// recordPrice guarda o preço no histórico. Uma falha aqui não impede a
+// operação que buscou o produto; o preço volta a ser registrado na próxima
+// sincronização.
+func recordPrice(prices productdomain.PriceHistoryRepository, p productdomain.Product, at time.Time) {
+	if err := prices.Record(p.ID, p.Price, at); err != nil {
+		log.Printf("price history: recording product %d: %v", p.ID, err)
+	}
+}
@@ -0,0 +1,195 @@
+package favorite_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
+	productdomain "github.com/vinihss/aiqfome/internal/domain/product"
+	"github.com/vinihss/aiqfome/internal/infrastructure/external_epis"
+	"github.com/vinihss/aiqfome/internal/infrastructure/httpretry"
+	"github.com/vinihss/aiqfome/internal/infrastructure/productstub"
+	"github.com/vinihss/aiqfome/internal/infrastructure/productstub/productstubtest"
+	usecase "github.com/vinihss/aiqfome/internal/usecases/favorite"
+)
+
+// memoryRepository guarda os favoritos em memória.
+type memoryRepository struct {
+	mutex     sync.Mutex
+	favorites []domain.Favorite
+}
+
+func (r *memoryRepository) Create(f domain.Favorite) (domain.Favorite, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	f.ID = uint(len(r.favorites) + 1)
+	f.FavoritedPrice = f.Price
+	r.favorites = append(r.favorites, f)
+	return f, nil
+}
+
+func (r *memoryRepository) Exists(customerID, productID uint) (bool, error) {
+	_, err := r.Find(customerID, productID)
+	return err == nil, nil
+}
+
+func (r *memoryRepository) Find(customerID, productID uint) (domain.Favorite, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, f := range r.favorites {
+		if f.CustomerID == customerID && f.ProductID == productID {
+			return f, nil
+		}
+	}
+	return domain.Favorite{}, domain.ErrNotFound
+}
+
+func (r *memoryRepository) ListByCustomer(domain.ListQuery) (domain.Page, error) {
+	return domain.Page{}, errors.New("not implemented")
+}
+
+func (r *memoryRepository) Delete(uint, uint) error {
+	return errors.New("not implemented")
+}
+
+func (r *memoryRepository) count() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.favorites)
+}
+
+// memoryPrices registra os preços gravados no histórico.
+type memoryPrices struct {
+	mutex  sync.Mutex
+	points []productdomain.PricePoint
+}
+
+func (p *memoryPrices) Record(productID uint, price float32, at time.Time) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.points = append(p.points, productdomain.PricePoint{ProductID: productID, Price: price, RecordedAt: at})
+	return nil
+}
+
+func (p *memoryPrices) History(uint, int) ([]productdomain.PricePoint, error) {
+	return nil, errors.New("not implemented")
+}
+
+type addFixture struct {
+	uc      *usecase.AddFavoriteUseCase
+	repo    *memoryRepository
+	prices  *memoryPrices
+	stub    *productstub.Server
+	breaker *external_epis.CircuitBreaker
+}
+
+func newAddFixture(t *testing.T, retry httpretry.Policy) addFixture {
+	t.Helper()
+	srv := productstubtest.NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	breaker := external_epis.NewCircuitBreaker(external_epis.BreakerSettings{
+		FailureThreshold:  2,
+		Window:            time.Minute,
+		CooldownPeriod:    time.Hour,
+		HalfOpenMaxProbes: 1,
+	})
+	provider := external_epis.NewHTTPProvider(srv.URL, "", time.Second, retry)
+	catalog := external_epis.NewCachedCatalog(provider, nil, breaker, 5*time.Second)
+
+	repo := &memoryRepository{}
+	prices := &memoryPrices{}
+	return addFixture{
+		uc:      usecase.NewAddFavoriteUseCase(repo, catalog, prices),
+		repo:    repo,
+		prices:  prices,
+		stub:    srv.Stub,
+		breaker: breaker,
+	}
+}
+
+func fixtureProduct(t *testing.T, id uint) external_epis.ExternalProduct {
+	t.Helper()
+	for _, p := range productstub.DefaultFixtures() {
+		if p.ID == id {
+			return p
+		}
+	}
+	t.Fatalf("product %d is not in the fixtures", id)
+	return external_epis.ExternalProduct{}
+}
+
+func TestAddFavoriteCopiesProductFromCatalog(t *testing.T) {
+	f := newAddFixture(t, httpretry.Policy{MaxAttempts: 1})
+	want := fixtureProduct(t, 5)
+
+	fav, err := f.uc.Execute(context.Background(), 42, 5)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if fav.CustomerID != 42 || fav.ProductID != 5 || fav.Title != want.Title || fav.ImageUrl != want.Image ||
+		fav.Price != want.Price || fav.Category != want.Category || !fav.Available || fav.LastSyncedAt == nil {
+		t.Errorf("favorite = %+v, want a snapshot of %+v", fav, want)
+	}
+	if len(f.prices.points) != 1 || f.prices.points[0].Price != want.Price {
+		t.Errorf("recorded prices = %+v, want one point at %v", f.prices.points, want.Price)
+	}
+
+	if _, err := f.uc.Execute(context.Background(), 42, 5); !errors.Is(err, usecase.ErrAlreadyFavorited) {
+		t.Errorf("second Execute error = %v, want ErrAlreadyFavorited", err)
+	}
+}
+
+func TestAddFavoriteUnknownProduct(t *testing.T) {
+	f := newAddFixture(t, httpretry.Policy{MaxAttempts: 1})
+	f.stub.SetFaults(productstub.Faults{Missing: []uint{3}})
+
+	if _, err := f.uc.Execute(context.Background(), 42, 3); !errors.Is(err, productdomain.ErrNotFound) {
+		t.Fatalf("Execute error = %v, want product.ErrNotFound", err)
+	}
+	if f.repo.count() != 0 {
+		t.Error("a favorite was stored for a missing product")
+	}
+}
+
+func TestAddFavoriteRetriesTransientFailures(t *testing.T) {
+	f := newAddFixture(t, httpretry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	f.stub.FailNext(2, http.StatusBadGateway)
+
+	if _, err := f.uc.Execute(context.Background(), 42, 1); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got := f.stub.Requests(); got != 3 {
+		t.Errorf("upstream requests = %d, want 3", got)
+	}
+	if got := f.breaker.State(); got != external_epis.StateClosed {
+		t.Errorf("breaker state = %s, want closed: retried calls count once", got)
+	}
+}
+
+func TestAddFavoriteFailsFastWhenBreakerIsOpen(t *testing.T) {
+	f := newAddFixture(t, httpretry.Policy{MaxAttempts: 1})
+	f.stub.FailNext(100, http.StatusServiceUnavailable)
+
+	for _, id := range []uint{1, 2} {
+		if _, err := f.uc.Execute(context.Background(), 42, id); err == nil {
+			t.Fatalf("Execute(%d) succeeded during the outage", id)
+		}
+	}
+
+	before := f.stub.Requests()
+	_, err := f.uc.Execute(context.Background(), 42, 3)
+	if !errors.Is(err, productdomain.ErrUnavailable) {
+		t.Fatalf("Execute error = %v, want product.ErrUnavailable", err)
+	}
+	if got := f.stub.Requests(); got != before {
+		t.Errorf("upstream requests while open = %d, want %d", got, before)
+	}
+	if f.repo.count() != 0 {
+		t.Error("a favorite was stored while the catalog was down")
+	}
+}
@@ -2,6 +2,7 @@ package favorite
 
 import (
 	"context"
+
 	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
 )
 
@@ -13,6 +14,21 @@ func NewListFavoritesUseCase(repo domain.Repository) *ListFavoritesUseCase {
 	return &ListFavoritesUseCase{repo: repo}
 }
 
-func (uc *ListFavoritesUseCase) Execute(_ context.Context, customerID uint) ([]domain.Favorite, error) {
-	return uc.repo.ListByCustomer(customerID)
+// Execute aplica os valores padrão da listagem (20 itens, mais recentes
+// primeiro) e rejeita consultas fora dos limites com domain.ErrInvalidQuery.
+func (uc *ListFavoritesUseCase) Execute(_ context.Context, q domain.ListQuery) (domain.Page, error) {
+	if q.Limit == 0 {
+		q.Limit = domain.DefaultListLimit
+	}
+	if q.Sort == "" {
+		q.Sort = domain.SortCreatedAt
+		q.Desc = true
+	}
+	if q.Limit < 0 || q.Limit > domain.MaxListLimit || !domain.IsValidSort(q.Sort) {
+		return domain.Page{}, domain.ErrInvalidQuery
+	}
+	if q.MinPrice != nil && q.MaxPrice != nil && *q.MinPrice > *q.MaxPrice {
+		return domain.Page{}, domain.ErrInvalidQuery
+	}
+	return uc.repo.ListByCustomer(q)
 }
@@ -0,0 +1,37 @@
+package favorite
+
+import (
+	"context"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
+	productdomain "github.com/vinihss/aiqfome/internal/domain/product"
+)
+
+// PriceHistory é o histórico de preços de um produto favoritado pelo cliente.
+type PriceHistory struct {
+	Favorite domain.Favorite
+	Points   []productdomain.PricePoint
+}
+
+type PriceHistoryUseCase struct {
+	repo   domain.Repository
+	prices productdomain.PriceHistoryRepository
+}
+
+func NewPriceHistoryUseCase(repo domain.Repository, prices productdomain.PriceHistoryRepository) *PriceHistoryUseCase {
+	return &PriceHistoryUseCase{repo: repo, prices: prices}
+}
+
+// Execute só devolve o histórico de produtos que o cliente favoritou; para os
+// demais devolve domain.ErrNotFound.
+func (uc *PriceHistoryUseCase) Execute(_ context.Context, customerID, productID uint, limit int) (PriceHistory, error) {
+	fav, err := uc.repo.Find(customerID, productID)
+	if err != nil {
+		return PriceHistory{}, err
+	}
+	points, err := uc.prices.History(productID, limit)
+	if err != nil {
+		return PriceHistory{}, err
+	}
+	return PriceHistory{Favorite: fav, Points: points}, nil
+}
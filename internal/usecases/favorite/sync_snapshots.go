@@ -0,0 +1,192 @@
+package favorite
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
+	productdomain "github.com/vinihss/aiqfome/internal/domain/product"
+)
+
+var ErrSyncInProgress = errors.New("sincronização de favoritos já em andamento em outra instância")
+
+// SyncOptions controla a sincronização dos dados de produto dos favoritos.
+type SyncOptions struct {
+	// MaxAge é a idade a partir da qual um favorito volta a ser sincronizado.
+	MaxAge time.Duration
+	// BatchSize é quantos produtos são buscados no catálogo por vez.
+	BatchSize int
+	// RatePerSecond limita quantos produtos são consultados por segundo.
+	RatePerSecond int
+}
+
+// PriceWatcher é avisado de cada preço obtido pela sincronização, por
+// exemplo para avaliar alertas de queda de preço.
+type PriceWatcher interface {
+	PriceObserved(ctx context.Context, productID uint, price float32) error
+}
+
+// SyncReport resume uma execução da sincronização.
+type SyncReport struct {
+	Products    int
+	Updated     int
+	Unavailable int
+	Failed      int
+}
+
+// SyncSnapshotsUseCase atualiza título, imagem e preço guardados nos
+// favoritos a partir do catálogo, um produto distinto por vez.
+//
+// A execução é retomável: só são visitados produtos com algum favorito
+// sincronizado há mais de MaxAge, então uma execução interrompida continua
+// de onde parou na próxima.
+type SyncSnapshotsUseCase struct {
+	repo    domain.SnapshotRepository
+	catalog productdomain.Catalog
+	prices  productdomain.PriceHistoryRepository
+	watcher PriceWatcher
+	opts    SyncOptions
+	now     func() time.Time
+}
+
+// NewSyncSnapshotsUseCase aceita watcher nil.
+func NewSyncSnapshotsUseCase(repo domain.SnapshotRepository, catalog productdomain.Catalog, prices productdomain.PriceHistoryRepository, watcher PriceWatcher, opts SyncOptions) *SyncSnapshotsUseCase {
+	return &SyncSnapshotsUseCase{repo: repo, catalog: catalog, prices: prices, watcher: watcher, opts: opts, now: time.Now}
+}
+
+// Execute faz uma passada completa. Com o catálogo indisponível a passada é
+// interrompida e o erro devolvido junto com o que já foi feito.
+func (uc *SyncSnapshotsUseCase) Execute(ctx context.Context) (SyncReport, error) {
+	var report SyncReport
+	acquired, err := uc.repo.WithSyncLock(ctx, func(ctx context.Context) error {
+		var err error
+		report, err = uc.sync(ctx)
+		return err
+	})
+	if err != nil {
+		return report, err
+	}
+	if !acquired {
+		return report, ErrSyncInProgress
+	}
+	return report, nil
+}
+
+func (uc *SyncSnapshotsUseCase) sync(ctx context.Context) (SyncReport, error) {
+	var report SyncReport
+	cutoff := uc.now().Add(-uc.opts.MaxAge)
+
+	var afterID uint
+	for {
+		ids, err := uc.repo.StaleProductIDs(afterID, cutoff, uc.opts.BatchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(ids) == 0 {
+			return report, nil
+		}
+		afterID = ids[len(ids)-1]
+
+		started := time.Now()
+		results := uc.catalog.GetProducts(ctx, ids)
+		syncedAt := uc.now()
+		for _, id := range ids {
+			report.Products++
+			res := results[id]
+			switch {
+			case res.Err == nil:
+				recordPrice(uc.prices, res.Product, syncedAt)
+				err = uc.repo.UpdateSnapshot(domain.Snapshot{
+					ProductID: id,
+					Title:     res.Product.Title,
+					ImageUrl:  res.Product.Image,
+					Category:  res.Product.Category,
+					Price:     res.Product.Price,
+				}, syncedAt)
+				if err == nil {
+					report.Updated++
+					uc.notify(ctx, res.Product)
+				}
+			case errors.Is(res.Err, productdomain.ErrNotFound):
+				err = uc.repo.MarkUnavailable(id, syncedAt)
+				if err == nil {
+					report.Unavailable++
+				}
+			case errors.Is(res.Err, productdomain.ErrUnavailable):
+				return report, res.Err
+			default:
+				// Produtos com erro ficam para a próxima passada.
+				report.Failed++
+				log.Printf("favorites sync: fetching product %d: %v", id, res.Err)
+				continue
+			}
+			if err != nil {
+				return report, err
+			}
+		}
+
+		if err := uc.throttle(ctx, len(ids), time.Since(started)); err != nil {
+			return report, err
+		}
+	}
+}
+
+// notify repassa o preço ao watcher. Uma falha não interrompe a
+// sincronização; o preço é avaliado de novo na próxima passada.
+func (uc *SyncSnapshotsUseCase) notify(ctx context.Context, p productdomain.Product) {
+	if uc.watcher == nil {
+		return
+	}
+	if err := uc.watcher.PriceObserved(ctx, p.ID, p.Price); err != nil {
+		log.Printf("favorites sync: notifying price of product %d: %v", p.ID, err)
+	}
+}
+
+// throttle espera o necessário para que n produtos consultados em elapsed
+// respeitem RatePerSecond.
+func (uc *SyncSnapshotsUseCase) throttle(ctx context.Context, n int, elapsed time.Duration) error {
+	if uc.opts.RatePerSecond <= 0 {
+		return ctx.Err()
+	}
+	wait := time.Duration(n)*time.Second/time.Duration(uc.opts.RatePerSecond) - elapsed
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Run executa Execute logo ao iniciar e depois a cada interval, até ctx ser
+// cancelado. Erros de uma passada são registrados e não interrompem o
+// agendamento.
+func (uc *SyncSnapshotsUseCase) Run(ctx context.Context, interval time.Duration) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+
+		report, err := uc.Execute(ctx)
+		switch {
+		case errors.Is(err, ErrSyncInProgress):
+		case err != nil && ctx.Err() == nil:
+			log.Printf("favorites sync: %v (%d products, %d updated, %d unavailable, %d failed)",
+				err, report.Products, report.Updated, report.Unavailable, report.Failed)
+		case err == nil:
+			log.Printf("favorites sync: %d products, %d updated, %d unavailable, %d failed",
+				report.Products, report.Updated, report.Unavailable, report.Failed)
+		}
+		timer.Reset(interval)
+	}
+}
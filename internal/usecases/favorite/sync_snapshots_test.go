@@ -0,0 +1,244 @@
+package favorite_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/vinihss/aiqfome/internal/domain/favorite"
+	productdomain "github.com/vinihss/aiqfome/internal/domain/product"
+	usecase "github.com/vinihss/aiqfome/internal/usecases/favorite"
+)
+
+// memorySnapshots guarda, por produto, quando os favoritos foram
+// sincronizados pela última vez e o que foi gravado.
+type memorySnapshots struct {
+	mutex       sync.Mutex
+	syncedAt    map[uint]*time.Time
+	snapshots   map[uint]domain.Snapshot
+	unavailable map[uint]bool
+	locked      bool
+	// afterIDs registra o afterID de cada chamada a StaleProductIDs.
+	afterIDs []uint
+}
+
+func newMemorySnapshots(productIDs ...uint) *memorySnapshots {
+	r := &memorySnapshots{
+		syncedAt:    map[uint]*time.Time{},
+		snapshots:   map[uint]domain.Snapshot{},
+		unavailable: map[uint]bool{},
+	}
+	for _, id := range productIDs {
+		r.syncedAt[id] = nil
+	}
+	return r
+}
+
+func (r *memorySnapshots) StaleProductIDs(afterID uint, olderThan time.Time, limit int) ([]uint, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.afterIDs = append(r.afterIDs, afterID)
+	var ids []uint
+	for id, at := range r.syncedAt {
+		if id > afterID && (at == nil || at.Before(olderThan)) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+func (r *memorySnapshots) UpdateSnapshot(s domain.Snapshot, syncedAt time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.snapshots[s.ProductID] = s
+	r.unavailable[s.ProductID] = false
+	r.syncedAt[s.ProductID] = &syncedAt
+	return nil
+}
+
+func (r *memorySnapshots) MarkUnavailable(productID uint, syncedAt time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.unavailable[productID] = true
+	r.syncedAt[productID] = &syncedAt
+	return nil
+}
+
+func (r *memorySnapshots) WithSyncLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	if r.locked {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+// fakeCatalog responde com o resultado configurado para cada produto e
+// registra os IDs consultados.
+type fakeCatalog struct {
+	mutex     sync.Mutex
+	results   map[uint]productdomain.Result
+	requested []uint
+}
+
+func (c *fakeCatalog) GetProduct(ctx context.Context, id uint) (productdomain.Product, error) {
+	res := c.GetProducts(ctx, []uint{id})[id]
+	return res.Product, res.Err
+}
+
+func (c *fakeCatalog) GetProducts(_ context.Context, ids []uint) map[uint]productdomain.Result {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	out := make(map[uint]productdomain.Result, len(ids))
+	for _, id := range ids {
+		c.requested = append(c.requested, id)
+		out[id] = c.results[id]
+	}
+	return out
+}
+
+func (c *fakeCatalog) set(id uint, res productdomain.Result) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.results[id] = res
+}
+
+func found(id uint, price float32) productdomain.Result {
+	return productdomain.Result{Product: productdomain.Product{ID: id, Title: "produto", Price: price, Category: "bags"}}
+}
+
+// priceLog registra os preços repassados ao watcher.
+type priceLog struct {
+	mutex    sync.Mutex
+	observed []uint
+}
+
+func (w *priceLog) PriceObserved(_ context.Context, productID uint, _ float32) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.observed = append(w.observed, productID)
+	return nil
+}
+
+type syncFixture struct {
+	uc      *usecase.SyncSnapshotsUseCase
+	repo    *memorySnapshots
+	catalog *fakeCatalog
+	prices  *memoryPrices
+	watcher *priceLog
+}
+
+func newSyncFixture(batchSize int, results map[uint]productdomain.Result) syncFixture {
+	ids := make([]uint, 0, len(results))
+	for id := range results {
+		ids = append(ids, id)
+	}
+	f := syncFixture{
+		repo:    newMemorySnapshots(ids...),
+		catalog: &fakeCatalog{results: results},
+		prices:  &memoryPrices{},
+		watcher: &priceLog{},
+	}
+	f.uc = usecase.NewSyncSnapshotsUseCase(f.repo, f.catalog, f.prices, f.watcher, usecase.SyncOptions{
+		MaxAge:    time.Hour,
+		BatchSize: batchSize,
+	})
+	return f
+}
+
+func TestSyncUpdatesSnapshots(t *testing.T) {
+	f := newSyncFixture(2, map[uint]productdomain.Result{1: found(1, 10), 2: found(2, 20), 3: found(3, 30)})
+
+	report, err := f.uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if report != (usecase.SyncReport{Products: 3, Updated: 3}) {
+		t.Errorf("report = %+v, want 3 products updated", report)
+	}
+	if s := f.repo.snapshots[2]; s.Price != 20 || s.Category != "bags" {
+		t.Errorf("snapshot of product 2 = %+v", s)
+	}
+	if len(f.prices.points) != 3 || !slices.Equal(f.watcher.observed, []uint{1, 2, 3}) {
+		t.Errorf("prices recorded = %d, observed = %v; want every product", len(f.prices.points), f.watcher.observed)
+	}
+	// Os lotes avançam pelo último ID do lote anterior.
+	if want := []uint{0, 2, 3}; !slices.Equal(f.repo.afterIDs, want) {
+		t.Errorf("StaleProductIDs afterIDs = %v, want %v", f.repo.afterIDs, want)
+	}
+}
+
+func TestSyncMarksRemovedProductsUnavailable(t *testing.T) {
+	f := newSyncFixture(10, map[uint]productdomain.Result{
+		1: found(1, 10),
+		2: {Err: productdomain.ErrNotFound},
+		3: {Err: errors.New("erro ao decodificar JSON")},
+	})
+
+	report, err := f.uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if report != (usecase.SyncReport{Products: 3, Updated: 1, Unavailable: 1, Failed: 1}) {
+		t.Errorf("report = %+v", report)
+	}
+	if !f.repo.unavailable[2] {
+		t.Error("product 2 was not marked unavailable")
+	}
+	if f.repo.syncedAt[3] != nil {
+		t.Error("a failed product must stay stale to be retried")
+	}
+	if slices.Contains(f.watcher.observed, 2) {
+		t.Error("the watcher was notified about a removed product")
+	}
+}
+
+func TestSyncStopsWhenCatalogIsUnavailableAndResumes(t *testing.T) {
+	f := newSyncFixture(1, map[uint]productdomain.Result{
+		1: found(1, 10),
+		2: {Err: productdomain.ErrUnavailable},
+		3: found(3, 30),
+	})
+
+	report, err := f.uc.Execute(context.Background())
+	if !errors.Is(err, productdomain.ErrUnavailable) {
+		t.Fatalf("Execute error = %v, want product.ErrUnavailable", err)
+	}
+	if report != (usecase.SyncReport{Products: 2, Updated: 1}) {
+		t.Errorf("report = %+v, want the pass to stop at product 2", report)
+	}
+	if slices.Contains(f.catalog.requested, 3) {
+		t.Error("product 3 was fetched after the catalog became unavailable")
+	}
+
+	// Na passada seguinte só os produtos ainda pendentes são consultados.
+	f.catalog.set(2, found(2, 20))
+	f.catalog.requested = nil
+	report, err = f.uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("second Execute: %v", err)
+	}
+	if !slices.Equal(f.catalog.requested, []uint{2, 3}) {
+		t.Errorf("second pass fetched %v, want [2 3]", f.catalog.requested)
+	}
+	if report != (usecase.SyncReport{Products: 2, Updated: 2}) {
+		t.Errorf("second report = %+v", report)
+	}
+}
+
+func TestSyncSkipsWhenAnotherInstanceHoldsTheLock(t *testing.T) {
+	f := newSyncFixture(10, map[uint]productdomain.Result{1: found(1, 10)})
+	f.repo.locked = true
+
+	if _, err := f.uc.Execute(context.Background()); !errors.Is(err, usecase.ErrSyncInProgress) {
+		t.Fatalf("Execute error = %v, want ErrSyncInProgress", err)
+	}
+	if len(f.catalog.requested) != 0 {
+		t.Errorf("catalog was queried without the lock: %v", f.catalog.requested)
+	}
+}
@@ -0,0 +1,25 @@
+package product
+
+import "context"
+
+// CachePurger remove produtos do cache compartilhado entre as réplicas.
+type CachePurger interface {
+	Invalidate(ctx context.Context, id uint) error
+	Purge(ctx context.Context) error
+}
+
+type PurgeCacheUseCase struct {
+	cache CachePurger
+}
+
+func NewPurgeCacheUseCase(cache CachePurger) *PurgeCacheUseCase {
+	return &PurgeCacheUseCase{cache: cache}
+}
+
+// Execute remove o produto informado ou, com productID zero, o cache inteiro.
+func (uc *PurgeCacheUseCase) Execute(ctx context.Context, productID uint) error {
+	if productID == 0 {
+		return uc.cache.Purge(ctx)
+	}
+	return uc.cache.Invalidate(ctx, productID)
+}
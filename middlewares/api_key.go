@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vinihss/aiqfome/internal/domain/authentication"
+	authuse "github.com/vinihss/aiqfome/internal/usecases/authentication"
+)
+
+// APIKeyHeader é o header usado por sistemas internos para se autenticar.
+const APIKeyHeader = "X-API-Key"
+
+type APIKeyAuthenticator interface {
+	Authenticate(key string) (authentication.Principal, error)
+}
+
+// APIKeyAuth autentica a requisição pela chave enviada em X-API-Key.
+func APIKeyAuth(authenticator APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+			return
+		}
+
+		principal, err := authenticator.Authenticate(key)
+		if err != nil {
+			if errors.Is(err, authuse.ErrInvalidAPIKey) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+				return
+			}
+			log.Printf("api key authentication failed: %v", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Authentication unavailable"})
+			return
+		}
+
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// Authenticate escolhe o mecanismo pela presença do header X-API-Key e cai
+// para o JWT caso contrário, de forma que as rotas aceitem os dois tipos de
+// credencial e sejam autorizadas pelos mesmos escopos.
+func Authenticate(jwtAuth, apiKeyAuth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(APIKeyHeader) != "" {
+			apiKeyAuth(c)
+			return
+		}
+		jwtAuth(c)
+	}
+}
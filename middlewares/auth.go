@@ -3,13 +3,36 @@ package middlewares
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/vinihss/aiqfome/config"
+	"github.com/vinihss/aiqfome/internal/domain/authentication"
+	"log"
 	"net/http"
 	"strings"
 )
 
-var secret = []byte("secreto123")
+const (
+	claimsKey    = "auth.claims"
+	principalKey = "auth.principal"
+)
+
+// TokenVerifier fornece as chaves de verificação por kid e os algoritmos
+// aceitos. Tokens com qualquer outro algoritmo são rejeitados.
+type TokenVerifier interface {
+	Keyfunc(token *jwt.Token) (interface{}, error)
+	Methods() []string
+}
+
+// JWTAuth valida o access token e rejeita tokens cujo jti foi revogado em
+// /logout. Se a denylist estiver indisponível a requisição segue, já que o
+// access token tem vida curta e derrubar toda a API seria pior.
+func JWTAuth(cfg config.JWTConfig, verifier TokenVerifier, denylist authentication.TokenDenylist) gin.HandlerFunc {
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(verifier.Methods()),
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithAudience(cfg.Audience),
+		jwt.WithExpirationRequired(),
+	}
 
-func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -24,15 +47,56 @@ func JWTAuth() gin.HandlerFunc {
 		}
 
 		tokenStr := parts[1]
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			return secret, nil
-		})
+		claims := &Claims{}
+		token, err := jwt.ParseWithClaims(tokenStr, claims, verifier.Keyfunc, parserOptions...)
 
 		if err != nil || !token.Valid {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			return
 		}
 
+		customerID, err := claims.CustomerID()
+		if err != nil || claims.ID == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
+		}
+
+		revoked, err := denylist.IsRevoked(c.Request.Context(), claims.ID)
+		if err != nil {
+			log.Printf("token denylist unavailable: %v", err)
+		} else if revoked {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token revoked"})
+			return
+		}
+
+		c.Set(claimsKey, claims)
+		c.Set(principalKey, authentication.Principal{
+			Kind:       authentication.PrincipalCustomer,
+			CustomerID: customerID,
+			Roles:      claims.Roles,
+			Scopes:     authentication.ScopesForRoles(claims.Roles),
+		})
 		c.Next()
 	}
 }
+
+// ClaimsFromContext devolve as claims gravadas por JWTAuth.
+func ClaimsFromContext(c *gin.Context) (*Claims, bool) {
+	v, ok := c.Get(claimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := v.(*Claims)
+	return claims, ok
+}
+
+// PrincipalFromContext devolve o principal autenticado por JWTAuth ou
+// APIKeyAuth.
+func PrincipalFromContext(c *gin.Context) (authentication.Principal, bool) {
+	v, ok := c.Get(principalKey)
+	if !ok {
+		return authentication.Principal{}, false
+	}
+	principal, ok := v.(authentication.Principal)
+	return principal, ok
+}
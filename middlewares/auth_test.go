@@ -0,0 +1,48 @@
+package middlewares_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vinihss/aiqfome/internal/domain/authentication"
+	"github.com/vinihss/aiqfome/internal/infrastructure/redistest"
+	"github.com/vinihss/aiqfome/internal/infrastructure/sessions"
+	"github.com/vinihss/aiqfome/middlewares"
+)
+
+func TestJWTAuthRejectsDenylistedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	denylist := sessions.NewTokenDenylist(redistest.NewServer(t).Client(t))
+	r := gin.New()
+	r.GET("/me", middlewares.JWTAuth(testJWT, hmacVerifier{}, denylist), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	call := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// customerToken usa o jti "jti-<cliente>".
+	revoked := customerToken(t, 7, authentication.RoleCustomer)
+	kept := customerToken(t, 8, authentication.RoleCustomer)
+	if code := call(revoked); code != http.StatusOK {
+		t.Fatalf("status before logout = %d, want 200", code)
+	}
+
+	if err := denylist.Revoke(context.Background(), "jti-7", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if code := call(revoked); code != http.StatusUnauthorized {
+		t.Errorf("status of a revoked token = %d, want 401", code)
+	}
+	if code := call(kept); code != http.StatusOK {
+		t.Errorf("status of another token = %d, want 200", code)
+	}
+}
@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vinihss/aiqfome/internal/domain/authentication"
+)
+
+// RequireScopes permite a requisição apenas quando o principal tem todos os
+// escopos informados. Tokens de cliente recebem os escopos dos seus papéis;
+// API keys, os escopos com que foram criadas.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := PrincipalFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token required"})
+			return
+		}
+		for _, scope := range scopes {
+			if !principal.HasScope(scope) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Missing scope " + scope})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireOwnerOrAdmin permite a requisição apenas quando o cliente do token é
+// o mesmo do parâmetro de rota informado, ou quando o token tem o papel admin.
+// API keys não representam um cliente e são limitadas só pelos escopos.
+// Deve ser usado depois de JWTAuth ou APIKeyAuth.
+func RequireOwnerOrAdmin(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := PrincipalFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token required"})
+			return
+		}
+		if principal.Kind == authentication.PrincipalAPIKey || principal.HasRole(authentication.RoleAdmin) {
+			c.Next()
+			return
+		}
+
+		// IDs inválidos seguem para o handler, que responde 400.
+		resourceID, err := strconv.ParseUint(c.Param(param), 10, 64)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		if uint64(principal.CustomerID) != resourceID {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireServiceOrAdmin protege rotas que não pertencem a um único cliente,
+// como a listagem de clientes: só API keys e administradores passam.
+func RequireServiceOrAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := PrincipalFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token required"})
+			return
+		}
+		if principal.Kind != authentication.PrincipalAPIKey && !principal.HasRole(authentication.RoleAdmin) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRole permite a requisição apenas para tokens com o papel informado.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := PrincipalFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token required"})
+			return
+		}
+		if !principal.HasRole(role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,129 @@
+package middlewares_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/vinihss/aiqfome/config"
+	"github.com/vinihss/aiqfome/internal/domain/authentication"
+	authuse "github.com/vinihss/aiqfome/internal/usecases/authentication"
+	"github.com/vinihss/aiqfome/middlewares"
+)
+
+var testJWT = config.JWTConfig{Issuer: "aiqfome-test", Audience: "aiqfome-api"}
+
+var testSecret = []byte("authorization-test-secret")
+
+// hmacVerifier aceita só tokens HS256 assinados com testSecret.
+type hmacVerifier struct{}
+
+func (hmacVerifier) Keyfunc(*jwt.Token) (interface{}, error) { return testSecret, nil }
+func (hmacVerifier) Methods() []string                       { return []string{"HS256"} }
+
+type emptyDenylist struct{}
+
+func (emptyDenylist) Revoke(context.Context, string, time.Time) error { return nil }
+func (emptyDenylist) IsRevoked(context.Context, string) (bool, error) { return false, nil }
+
+// apiKeys autentica as chaves pelo valor, com os escopos cadastrados.
+type apiKeys map[string][]string
+
+func (k apiKeys) Authenticate(key string) (authentication.Principal, error) {
+	scopes, ok := k[key]
+	if !ok {
+		return authentication.Principal{}, authuse.ErrInvalidAPIKey
+	}
+	return authentication.Principal{Kind: authentication.PrincipalAPIKey, Scopes: scopes}, nil
+}
+
+// newRouter monta a mesma cadeia das rotas de favoritos: autenticação,
+// escopo e dono do recurso.
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	auth := middlewares.Authenticate(
+		middlewares.JWTAuth(testJWT, hmacVerifier{}, emptyDenylist{}),
+		middlewares.APIKeyAuth(apiKeys{
+			"reader-key":  {authentication.ScopeFavoritesRead},
+			"catalog-key": {authentication.ScopeProductsManage},
+		}),
+	)
+	r.GET("/customer/:id/favorites", auth,
+		middlewares.RequireScopes(authentication.ScopeFavoritesRead),
+		middlewares.RequireOwnerOrAdmin("id"),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func customerToken(t *testing.T, customerID uint, roles ...string) string {
+	t.Helper()
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &middlewares.Claims{
+		Roles: roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(customerID), 10),
+			ID:        "jti-" + strconv.FormatUint(uint64(customerID), 10),
+			Issuer:    testJWT.Issuer,
+			Audience:  jwt.ClaimStrings{testJWT.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	})
+	signed, err := token.SignedString(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestRequireOwnerOrAdmin(t *testing.T) {
+	r := newRouter()
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{"owner", "Authorization", "Bearer " + customerToken(t, 7, authentication.RoleCustomer), http.StatusOK},
+		{"another customer", "Authorization", "Bearer " + customerToken(t, 8, authentication.RoleCustomer), http.StatusForbidden},
+		{"admin", "Authorization", "Bearer " + customerToken(t, 1, authentication.RoleAdmin), http.StatusOK},
+		{"api key with scope", middlewares.APIKeyHeader, "reader-key", http.StatusOK},
+		{"api key without scope", middlewares.APIKeyHeader, "catalog-key", http.StatusForbidden},
+		{"no credentials", "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/customer/7/favorites", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestRequireOwnerOrAdminLetsInvalidIDReachHandler(t *testing.T) {
+	r := newRouter()
+	req := httptest.NewRequest(http.MethodGet, "/customer/abc/favorites", nil)
+	req.Header.Set("Authorization", "Bearer "+customerToken(t, 7, authentication.RoleCustomer))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	// O handler real responde 400; aqui basta não ser barrado pelo middleware.
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want the request to reach the handler", rec.Code)
+	}
+}
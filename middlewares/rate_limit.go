@@ -0,0 +1,71 @@
+package middlewares
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vinihss/aiqfome/config"
+	"github.com/vinihss/aiqfome/internal/domain/authentication"
+	"github.com/vinihss/aiqfome/internal/infrastructure/ratelimit"
+)
+
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, requests int, window time.Duration) (ratelimit.Result, error)
+}
+
+// RateLimit limita as requisições do grupo de rotas por principal: o cliente
+// do token, a API key ou, em rotas públicas, o IP de origem. Deve ser
+// registrado depois da autenticação para que o principal seja conhecido.
+// Se o Redis estiver indisponível a requisição segue sem limite; a falha e a
+// recuperação são registradas uma vez cada, e não a cada requisição.
+func RateLimit(limiter RateLimiter, group string, limit config.RateLimit) gin.HandlerFunc {
+	var unavailable atomic.Bool
+	return func(c *gin.Context) {
+		key := group + ":" + rateLimitSubject(c)
+		res, err := limiter.Allow(c.Request.Context(), key, limit.Requests, limit.Window)
+		if err != nil {
+			if unavailable.CompareAndSwap(false, true) {
+				log.Printf("rate limiter unavailable for %s, allowing requests: %v", group, err)
+			}
+			c.Next()
+			return
+		}
+		if unavailable.CompareAndSwap(true, false) {
+			log.Printf("rate limiter for %s recovered", group)
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("X-RateLimit-Reset", seconds(res.Reset))
+
+		if !res.Allowed {
+			c.Header("Retry-After", seconds(res.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func rateLimitSubject(c *gin.Context) string {
+	principal, ok := PrincipalFromContext(c)
+	if !ok {
+		return "ip:" + c.ClientIP()
+	}
+	if principal.Kind == authentication.PrincipalAPIKey {
+		return "api_key:" + strconv.FormatUint(uint64(principal.APIKeyID), 10)
+	}
+	return "customer:" + strconv.FormatUint(uint64(principal.CustomerID), 10)
+}
+
+// seconds arredonda para cima, já que os headers só aceitam segundos inteiros.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,64 @@
+package middlewares_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vinihss/aiqfome/config"
+	"github.com/vinihss/aiqfome/internal/infrastructure/ratelimit"
+	"github.com/vinihss/aiqfome/middlewares"
+)
+
+// flakyLimiter falha enquanto down for verdadeiro, como um Redis fora.
+type flakyLimiter struct {
+	down bool
+}
+
+func (l *flakyLimiter) Allow(_ context.Context, _ string, requests int, _ time.Duration) (ratelimit.Result, error) {
+	if l.down {
+		return ratelimit.Result{}, errors.New("dial tcp: connection refused")
+	}
+	return ratelimit.Result{Allowed: true, Limit: requests, Remaining: requests}, nil
+}
+
+func TestRateLimitLogsOutageOncePerStateChange(t *testing.T) {
+	var logs bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(previous) })
+
+	gin.SetMode(gin.TestMode)
+	limiter := &flakyLimiter{}
+	r := gin.New()
+	r.GET("/register", middlewares.RateLimit(limiter, "public", config.RateLimit{Requests: 5, Window: time.Minute}),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+	call := func() int {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/register", nil))
+		return rec.Code
+	}
+
+	for _, down := range []bool{false, true, true, true, false, false, true, true} {
+		limiter.down = down
+		if code := call(); code != http.StatusOK {
+			t.Fatalf("status = %d, want requests allowed while the limiter is down", code)
+		}
+	}
+
+	out := logs.String()
+	if got := strings.Count(out, "unavailable"); got != 2 {
+		t.Errorf("outage lines = %d, want 2:\n%s", got, out)
+	}
+	if got := strings.Count(out, "recovered"); got != 1 {
+		t.Errorf("recovery lines = %d, want 1:\n%s", got, out)
+	}
+}